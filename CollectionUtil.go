@@ -27,12 +27,16 @@ type collectionUtil struct {
 
 var Collection = collectionUtil{}
 
-func (this collectionUtil) NewLinkedOrderedMap() *collection.LinkedOrderedMap {
-	return collection.NewLinkedOrderedMap(cmp.Compare)
+func (this collectionUtil) NewLinkedOrderedMap() *collection.LinkedOrderedMap[interface{}, interface{}] {
+	return collection.NewLinkedOrderedMap[interface{}, interface{}](cmp.Compare)
 }
 
-func (this collectionUtil) NewLockfreeQueue() *collection.LockfreeQueue {
-	return collection.NewLockfreeQueue()
+func (this collectionUtil) NewLockfreeQueue() *collection.LockfreeQueue[interface{}] {
+	return collection.NewLockfreeQueue[interface{}]()
+}
+
+func (this collectionUtil) NewBoundedQueue(capacity int) *collection.BoundedQueue[interface{}] {
+	return collection.NewBoundedQueue[interface{}](capacity)
 }
 
 func (this collectionUtil) NewArrayList() *arraylist.List {
@@ -114,3 +118,100 @@ func (this collectionUtil) NewBinaryHeap() *binaryheap.Heap {
 func (this collectionUtil) NewBinaryHeapWithComparator(order int, comparator cmp.Comparator) *binaryheap.Heap {
 	return binaryheap.NewWith(utils.Comparator(comparator))
 }
+
+func (this collectionUtil) NewSynchronizedHashMap() *collection.SynchronizedMap {
+	return collection.NewSynchronizedMap(hashmap.New())
+}
+
+func (this collectionUtil) NewSynchronizedTreeMap() *collection.SynchronizedMap {
+	return collection.NewSynchronizedMap(treemap.NewWith(cmp.Compare))
+}
+
+func (this collectionUtil) NewSynchronizedTreeMapWithComparator(comparator cmp.Comparator) *collection.SynchronizedMap {
+	return collection.NewSynchronizedMap(treemap.NewWith(utils.Comparator(comparator)))
+}
+
+func (this collectionUtil) NewSynchronizedRedBlackTree() *collection.SynchronizedMap {
+	return collection.NewSynchronizedMap(redblacktree.NewWith(cmp.Compare))
+}
+
+func (this collectionUtil) NewSynchronizedHashSet() *collection.SynchronizedSet {
+	return collection.NewSynchronizedSet(hashset.New())
+}
+
+func (this collectionUtil) NewSynchronizedTreeSet() *collection.SynchronizedSet {
+	return collection.NewSynchronizedSet(treeset.NewWith(cmp.Compare))
+}
+
+func (this collectionUtil) NewSynchronizedArrayList() *collection.SynchronizedList {
+	return collection.NewSynchronizedList(arraylist.New())
+}
+
+func (this collectionUtil) NewSynchronizedDoublyLinkedList() *collection.SynchronizedList {
+	return collection.NewSynchronizedList(doublylinkedlist.New())
+}
+
+func (this collectionUtil) NewSyncLinkedOrderedMap() *collection.SyncLinkedOrderedMap[interface{}, interface{}] {
+	return collection.NewSyncLinkedOrderedMap[interface{}, interface{}](cmp.Compare)
+}
+
+func (this collectionUtil) NewConcurrentHashMap() *collection.ConcurrentHashMap[interface{}, interface{}] {
+	return collection.NewConcurrentHashMap[interface{}, interface{}](0)
+}
+
+func (this collectionUtil) NewPersistentVector() *collection.PersistentVector[interface{}] {
+	return collection.NewPersistentVector[interface{}]()
+}
+
+func (this collectionUtil) NewPersistentMap() *collection.PersistentMap[interface{}, interface{}] {
+	return collection.NewPersistentMap[interface{}, interface{}]()
+}
+
+func (this collectionUtil) NewPersistentSortedMap() *collection.PersistentSortedMap[interface{}, interface{}] {
+	return collection.NewPersistentSortedMap[interface{}, interface{}](cmp.Compare)
+}
+
+func (this collectionUtil) NewPriorityQueue(comparator cmp.Comparator) *collection.PriorityQueue[interface{}] {
+	return collection.NewPriorityQueue[interface{}](comparator)
+}
+
+func (this collectionUtil) NewDelayQueue() *collection.DelayQueue[interface{}] {
+	return collection.NewDelayQueue[interface{}]()
+}
+
+func (this collectionUtil) NewRadixTree() *collection.RadixTree[interface{}] {
+	return collection.NewRadixTree[interface{}]()
+}
+
+func (this collectionUtil) NewCIDRTree() *collection.CIDRTree[interface{}] {
+	return collection.NewCIDRTree[interface{}]()
+}
+
+func (this collectionUtil) NewRingBuffer(capacity int, policy collection.FullPolicy) *collection.RingBuffer[interface{}] {
+	return collection.NewRingBuffer[interface{}](capacity, policy)
+}
+
+func (this collectionUtil) NewHashMultiset() *collection.Multiset[interface{}] {
+	return collection.NewHashMultiset[interface{}]()
+}
+
+func (this collectionUtil) NewTreeMultiset(comparator cmp.Comparator) *collection.Multiset[interface{}] {
+	return collection.NewTreeMultiset[interface{}](comparator)
+}
+
+func (this collectionUtil) NewHashListMultimap() *collection.ListMultimap[interface{}, interface{}] {
+	return collection.NewHashListMultimap[interface{}, interface{}]()
+}
+
+func (this collectionUtil) NewHashSetMultimap() *collection.SetMultimap[interface{}, interface{}] {
+	return collection.NewHashSetMultimap[interface{}, interface{}]()
+}
+
+func (this collectionUtil) NewHashTable() *collection.Table[interface{}, interface{}, interface{}] {
+	return collection.NewHashTable[interface{}, interface{}, interface{}]()
+}
+
+// Stream returns a lazy Stream over the values of a container returned by Collection.
+func (this collectionUtil) Stream(container collection.Container) collection.Stream[interface{}] {
+	return collection.FromContainer(container)
+}
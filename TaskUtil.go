@@ -5,6 +5,8 @@ import (
 	"errors"
 	"sync"
 	"reflect"
+	"context"
+	"github.com/cnfree/common/task"
 )
 
 type taskUtil struct {
@@ -29,31 +31,48 @@ func (this taskUtil) ExecuteWithTimeout(f func() error, timeout time.Duration) (
 	}
 }
 
+// NewGroup returns a new task.Group and its derived context.
+// The context is canceled by the first subtask returning an error.
+func (this taskUtil) NewGroup(ctx context.Context) (*task.Group, context.Context) {
+	return task.NewGroup(ctx)
+}
+
+// NewLimitGroup is the same as NewGroup, but runs at most limit subtasks at the same time.
+func (this taskUtil) NewLimitGroup(ctx context.Context, limit int) (*task.Group, context.Context) {
+	g, ctx := task.NewGroup(ctx)
+	g.SetLimit(limit)
+	return g, ctx
+}
+
+// Retry calls op until it succeeds or the policy gives up. See task.Retry for details.
+func (this taskUtil) Retry(ctx context.Context, policy task.RetryPolicy, op func(context.Context) error) error {
+	return task.Retry(ctx, policy, op)
+}
+
+// Debounce returns a function which invokes f once the calls to it stopped for wait.
+// Use task.NewDebouncer for leading edge invocations or cancellation.
+func (this taskUtil) Debounce(f func(), wait time.Duration) func() {
+	return task.NewDebouncer(wait, f, task.Trailing).Call
+}
+
+// Throttle returns a function which invokes f at most once per interval, on both the leading and
+// the trailing edges. Use task.NewThrottler for other options.
+func (this taskUtil) Throttle(f func(), interval time.Duration) func() {
+	return task.NewThrottler(interval, f, task.Leading|task.Trailing).Call
+}
+
+// Deprecated: use task.FanIn, which is type-safe and doesn't need reflection.
 func (this taskUtil) MergeChannel(cs []chan reflect.Value) (out chan reflect.Value) {
 	out = make(chan reflect.Value)
 	this.MergeChannelTo(cs, nil, out)
 	return out
 }
 
+// Deprecated: use task.MergeTo, which is type-safe and doesn't need reflection.
 func (this taskUtil) MergeChannelTo(cs []chan reflect.Value, transformFn func(reflect.Value) reflect.Value, out chan reflect.Value) {
-	var wg sync.WaitGroup
-
-	for _, c := range cs {
-		wg.Add(1)
-		go func(c chan reflect.Value) {
-			defer wg.Done()
-			for n := range c {
-				if transformFn != nil {
-					n = transformFn(n)
-				}
-				out <- n
-			}
-		}(c)
+	in := make([]<-chan reflect.Value, len(cs))
+	for i, c := range cs {
+		in[i] = c
 	}
-
-	go func() {
-		wg.Wait()
-		close(out)
-	}()
-	return
+	task.MergeTo(context.Background(), in, transformFn, out)
 }
@@ -87,6 +87,9 @@ func canCompare(o1 Comparable, o2 Comparable) bool {
 }
 
 func isNumber(comparable Comparable) bool {
+	if isBigNumber(comparable) {
+		return true
+	}
 	switch reflect.TypeOf(comparable).Kind() {
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
@@ -112,21 +115,7 @@ func isSameType(o1 Comparable, o2 Comparable) bool {
 
 func compare(o1 Comparable, o2 Comparable) (int, error) {
 	if isNumber(o1) && isNumber(o2) {
-		number1, err := toFloat64(o1)
-		if err != nil {
-			return 0, nil
-		}
-		number2, err := toFloat64(o2)
-		if err != nil {
-			return 0, nil
-		}
-		if number1 > number2 {
-			return 1, nil
-		} else if number1 == number2 {
-			return 0, nil
-		} else {
-			return -1, nil
-		}
+		return compareNumbers(o1, o2)
 	} else if isString(o1) && isString(o2) {
 		string1, err := toString(o1)
 		if err != nil {
@@ -291,22 +280,28 @@ func ToComp(i interface{}) (Comparable, error) {
 	if m, ok := i.(Comparable); ok {
 		return m, nil
 	}
-	switch reflect.TypeOf(i).Kind() {
+	if i == nil {
+		return nil, ErrInvalid
+	}
+	if r, ok := lookupRegistration(reflect.TypeOf(i)); ok {
+		return r.wrap(i), nil
+	}
+	value := reflect.ValueOf(i)
+	switch value.Kind() {
 	case reflect.Float32:
-		return Comparable(FloatComp(i.(float32))), nil
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
-		return IntComp(int(reflect.ValueOf(i).Uint())), nil
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
-		return IntComp(int(reflect.ValueOf(i).Int())), nil
+		return Comparable(FloatComp(value.Float())), nil
+	case reflect.Int:
+		return IntComp(int(value.Int())), nil
 	case reflect.Float64:
-		return Comparable(Float64Comp(i.(float64))), nil
-	case reflect.Uint64:
-		return Comparable(Int64Comp(int64(i.(uint64)))), nil
+		return Comparable(Float64Comp(value.Float())), nil
 	case reflect.Int64:
-		return Comparable(Int64Comp(i.(int64))), nil
+		return Comparable(Int64Comp(value.Int())), nil
 	case reflect.String:
-		return Comparable(StringComp(i.(string))), nil
+		return Comparable(StringComp(value.String())), nil
 	default:
+		if c, ok := toComparableKind(value); ok {
+			return c, nil
+		}
 		return nil, ErrInvalid
 	}
 }
@@ -360,6 +355,9 @@ func ToInt(i Comparable) (int, error) {
 	if m, ok := i.(Float64Comp); ok {
 		return int(float64(m)), nil
 	}
+	if v, ok := toInt64(i); ok {
+		return int(v), nil
+	}
 	return 0, ErrInvalid
 }
 
@@ -391,6 +389,9 @@ func ToInt64(i Comparable) (int64, error) {
 	if m, ok := i.(Float64Comp); ok {
 		return int64(float64(m)), nil
 	}
+	if v, ok := toInt64(i); ok {
+		return v, nil
+	}
 	return 0, ErrInvalid
 }
 
@@ -426,6 +427,9 @@ func ToFloat(i Comparable) (float32, error) {
 	if m, ok := i.(Float64Comp); ok {
 		return float32(float64(m)), nil
 	}
+	if v, ok := toFloat64Extended(i); ok {
+		return float32(v), nil
+	}
 	return 0, ErrInvalid
 }
 
@@ -457,6 +461,9 @@ func ToFloat64(i Comparable) (float64, error) {
 	if m, ok := i.(Float64Comp); ok {
 		return float64(m), nil
 	}
+	if v, ok := toFloat64Extended(i); ok {
+		return v, nil
+	}
 	return 0, ErrInvalid
 }
 
@@ -474,7 +481,14 @@ func ToStringArray(s []Comparable) ([]string, error) {
 
 func ToElementArray(s []Comparable, elementType reflect.Type) (interface{}, error) {
 	b := reflect.Zero(reflect.SliceOf(elementType))
+	r, registered := lookupRegistration(elementType)
 	for _, elem := range s {
+		if registered {
+			if v, ok := r.unwrap(elem); ok {
+				b = reflect.Append(b, reflect.ValueOf(v))
+				continue
+			}
+		}
 		if reflect.TypeOf(elem) == elementType {
 			b = reflect.Append(b, reflect.ValueOf(elem))
 			continue
@@ -531,5 +545,8 @@ func ToString(i Comparable) (string, error) {
 	if m, ok := i.(Float64Comp); ok {
 		return fmt.Sprint(float64(m)), nil
 	}
+	if v, ok := toStringExtended(i); ok {
+		return v, nil
+	}
 	return "", ErrInvalid
 }
@@ -0,0 +1,324 @@
+package cmp
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var _ Comparable = Int8Comp(0)
+var _ Comparable = Int16Comp(0)
+var _ Comparable = Int32Comp(0)
+var _ Comparable = UintComp(0)
+var _ Comparable = Uint8Comp(0)
+var _ Comparable = Uint16Comp(0)
+var _ Comparable = Uint32Comp(0)
+var _ Comparable = Uint64Comp(0)
+var _ Comparable = BigIntComp{}
+var _ Comparable = BigRatComp{}
+var _ Comparable = TimeComp{}
+var _ Comparable = DurationComp(0)
+var _ Comparable = BoolComp(false)
+
+type Int8Comp int8
+type Int16Comp int16
+type Int32Comp int32
+type UintComp uint
+type Uint8Comp uint8
+type Uint16Comp uint16
+type Uint32Comp uint32
+type Uint64Comp uint64
+
+// BigIntComp is a Comparable *big.Int, compared exactly with the other numbers.
+type BigIntComp struct {
+	*big.Int
+}
+
+// BigRatComp is a Comparable *big.Rat, compared exactly with the other numbers.
+type BigRatComp struct {
+	*big.Rat
+}
+
+// TimeComp is a Comparable time.Time, only comparable with other TimeComp values.
+type TimeComp struct {
+	time.Time
+}
+
+// DurationComp is a Comparable time.Duration, compared with the other numbers as a number of nanoseconds.
+type DurationComp time.Duration
+
+// BoolComp is a Comparable bool, false being less than true, only comparable with other BoolComp values.
+type BoolComp bool
+
+func (this Int8Comp) CompareTo(o Comparable) (int, error) {
+	return compareTo(this, o)
+}
+
+func (this Int16Comp) CompareTo(o Comparable) (int, error) {
+	return compareTo(this, o)
+}
+
+func (this Int32Comp) CompareTo(o Comparable) (int, error) {
+	return compareTo(this, o)
+}
+
+func (this UintComp) CompareTo(o Comparable) (int, error) {
+	return compareTo(this, o)
+}
+
+func (this Uint8Comp) CompareTo(o Comparable) (int, error) {
+	return compareTo(this, o)
+}
+
+func (this Uint16Comp) CompareTo(o Comparable) (int, error) {
+	return compareTo(this, o)
+}
+
+func (this Uint32Comp) CompareTo(o Comparable) (int, error) {
+	return compareTo(this, o)
+}
+
+func (this Uint64Comp) CompareTo(o Comparable) (int, error) {
+	return compareTo(this, o)
+}
+
+func (this BigIntComp) CompareTo(o Comparable) (int, error) {
+	if v, ok := o.(BigIntComp); ok {
+		return this.Int.Cmp(v.Int), nil
+	}
+	return compareTo(this, o)
+}
+
+func (this BigRatComp) CompareTo(o Comparable) (int, error) {
+	if v, ok := o.(BigRatComp); ok {
+		return this.Rat.Cmp(v.Rat), nil
+	}
+	return compareTo(this, o)
+}
+
+func (this DurationComp) CompareTo(o Comparable) (int, error) {
+	return compareTo(this, o)
+}
+
+func (this TimeComp) CompareTo(o Comparable) (int, error) {
+	if v, ok := o.(TimeComp); ok {
+		return this.Time.Compare(v.Time), nil
+	}
+	return 0, &CompareError{"TimeComp", reflect.TypeOf(o).Name(), ErrInvalid}
+}
+
+func (this BoolComp) CompareTo(o Comparable) (int, error) {
+	if v, ok := o.(BoolComp); ok {
+		switch {
+		case this == v:
+			return 0, nil
+		case bool(this):
+			return 1, nil
+		}
+		return -1, nil
+	}
+	return 0, &CompareError{"BoolComp", reflect.TypeOf(o).Name(), ErrInvalid}
+}
+
+// isBigNumber returns true for the Comparable numbers which aren't of a numeric kind.
+func isBigNumber(comparable Comparable) bool {
+	switch comparable.(type) {
+	case BigIntComp, BigRatComp:
+		return true
+	}
+	return false
+}
+
+// compareNumbers compares numbers of any types exactly, even the large integers floats can't represent.
+func compareNumbers(o1 Comparable, o2 Comparable) (int, error) {
+	r1, f1, err := toRat(o1)
+	if err != nil {
+		return 0, err
+	}
+	r2, f2, err := toRat(o2)
+	if err != nil {
+		return 0, err
+	}
+	if r1 == nil || r2 == nil {
+		// NaN or infinity
+		if r1 != nil {
+			f1, _ = r1.Float64()
+		}
+		if r2 != nil {
+			f2, _ = r2.Float64()
+		}
+		return CompareOrdered(f1, f2), nil
+	}
+	return r1.Cmp(r2), nil
+}
+
+// toRat returns the exact value of a number, or nil and its float value if it's NaN or infinite.
+func toRat(comparable Comparable) (*big.Rat, float64, error) {
+	switch v := comparable.(type) {
+	case BigIntComp:
+		return new(big.Rat).SetInt(v.Int), 0, nil
+	case BigRatComp:
+		return v.Rat, 0, nil
+	}
+	value := reflect.ValueOf(comparable)
+	switch {
+	case value.CanInt():
+		return new(big.Rat).SetInt64(value.Int()), 0, nil
+	case value.CanUint():
+		return new(big.Rat).SetUint64(value.Uint()), 0, nil
+	case value.CanFloat():
+		f := value.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, f, nil
+		}
+		return new(big.Rat).SetFloat64(f), 0, nil
+	}
+	return nil, 0, &ConvertError{value.Type().Name(), "number", ErrInvalid}
+}
+
+// registration holds the conversions of a type registered with RegisterComparable.
+type registration struct {
+	wrap   func(interface{}) Comparable
+	unwrap func(Comparable) (interface{}, bool)
+}
+
+var registry sync.Map // reflect.Type -> registration
+
+// RegisterComparable registers the conversions between a type T and a Comparable type C, so that ToComp and
+// ToCompArray convert the values of type T with wrap, and ToElementArray converts back the values of type C
+// with unwrap when it's asked for elements of type T.
+// It's typically called in an init function.
+//
+// Example:
+//
+//   type Money struct{ Cents int64 }
+//   type MoneyComp Money
+//
+//   func (m MoneyComp) CompareTo(o cmp.Comparable) (int, error) {
+//       if v, ok := o.(MoneyComp); ok {
+//           return cmp.CompareOrdered(m.Cents, v.Cents), nil
+//       }
+//       return 0, cmp.ErrInvalid
+//   }
+//
+//   func init() {
+//       cmp.RegisterComparable(func(m Money) MoneyComp { return MoneyComp(m) },
+//           func(m MoneyComp) Money { return Money(m) })
+//   }
+func RegisterComparable[T any, C Comparable](wrap func(T) C, unwrap func(C) T) {
+	registry.Store(reflect.TypeFor[T](), registration{
+		wrap: func(v interface{}) Comparable {
+			return wrap(v.(T))
+		},
+		unwrap: func(c Comparable) (interface{}, bool) {
+			v, ok := c.(C)
+			if !ok {
+				return nil, false
+			}
+			return unwrap(v), true
+		},
+	})
+}
+
+func lookupRegistration(t reflect.Type) (registration, bool) {
+	r, ok := registry.Load(t)
+	if !ok {
+		return registration{}, false
+	}
+	return r.(registration), true
+}
+
+func init() {
+	RegisterComparable(func(v *big.Int) BigIntComp { return BigIntComp{v} }, func(c BigIntComp) *big.Int { return c.Int })
+	RegisterComparable(func(v *big.Rat) BigRatComp { return BigRatComp{v} }, func(c BigRatComp) *big.Rat { return c.Rat })
+	RegisterComparable(func(v time.Time) TimeComp { return TimeComp{v} }, func(c TimeComp) time.Time { return c.Time })
+	RegisterComparable(func(v time.Duration) DurationComp { return DurationComp(v) }, func(c DurationComp) time.Duration { return time.Duration(c) })
+}
+
+// toComparableKind converts the values of the kinds missing from the original conversions of ToComp.
+func toComparableKind(value reflect.Value) (Comparable, bool) {
+	switch value.Kind() {
+	case reflect.Int8:
+		return Int8Comp(value.Int()), true
+	case reflect.Int16:
+		return Int16Comp(value.Int()), true
+	case reflect.Int32:
+		return Int32Comp(value.Int()), true
+	case reflect.Uint:
+		return UintComp(value.Uint()), true
+	case reflect.Uint8:
+		return Uint8Comp(value.Uint()), true
+	case reflect.Uint16:
+		return Uint16Comp(value.Uint()), true
+	case reflect.Uint32:
+		return Uint32Comp(value.Uint()), true
+	case reflect.Uint64:
+		return Uint64Comp(value.Uint()), true
+	case reflect.Bool:
+		return BoolComp(value.Bool()), true
+	}
+	return nil, false
+}
+
+// toInt64 converts the Comparable numbers added after IntComp, Int64Comp, FloatComp and Float64Comp.
+func toInt64(i Comparable) (int64, bool) {
+	switch v := i.(type) {
+	case BigIntComp:
+		return v.Int64(), true
+	case BigRatComp:
+		f, _ := v.Float64()
+		return int64(f), true
+	}
+	value := reflect.ValueOf(i)
+	switch {
+	case value.CanInt():
+		return value.Int(), true
+	case value.CanUint():
+		return int64(value.Uint()), true
+	case value.CanFloat():
+		return int64(value.Float()), true
+	}
+	return 0, false
+}
+
+// toFloat64Extended converts the Comparable numbers added after IntComp, Int64Comp, FloatComp and Float64Comp.
+func toFloat64Extended(i Comparable) (float64, bool) {
+	switch v := i.(type) {
+	case BigIntComp:
+		f, _ := new(big.Float).SetInt(v.Int).Float64()
+		return f, true
+	case BigRatComp:
+		f, _ := v.Float64()
+		return f, true
+	}
+	value := reflect.ValueOf(i)
+	switch {
+	case value.CanInt():
+		return float64(value.Int()), true
+	case value.CanUint():
+		return float64(value.Uint()), true
+	case value.CanFloat():
+		return value.Float(), true
+	}
+	return 0, false
+}
+
+// toStringExtended formats the Comparable values added after StringComp and the original numbers.
+func toStringExtended(i Comparable) (string, bool) {
+	switch v := i.(type) {
+	case BigIntComp:
+		return v.String(), true
+	case BigRatComp:
+		return v.RatString(), true
+	case TimeComp:
+		return v.Format(time.RFC3339Nano), true
+	case DurationComp:
+		return time.Duration(v).String(), true
+	case BoolComp, Int8Comp, Int16Comp, Int32Comp, UintComp, Uint8Comp, Uint16Comp, Uint32Comp, Uint64Comp:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
@@ -14,6 +14,22 @@ import (
 type Comparator func(a, b interface{}) int
 
 func Compare(a interface{}, b interface{}) int {
+	// fast path without reflection for the most common key types
+	switch x := a.(type) {
+	case int:
+		if y, ok := b.(int); ok {
+			return CompareOrdered(x, y)
+		}
+	case int64:
+		if y, ok := b.(int64); ok {
+			return CompareOrdered(x, y)
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return CompareOrdered(x, y)
+		}
+	}
+
 	value1 := reflect.ValueOf(a)
 	value2 := reflect.ValueOf(b)
 
@@ -0,0 +1,35 @@
+package cmp
+
+// Ordered is a constraint that permits any ordered type: any type that supports the operators < <= >= >.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 |
+		~string
+}
+
+// CompareOrdered returns
+//     -1 if a is less than b,
+//      0 if a equals b,
+//     +1 if a is greater than b.
+// For floating-point types, a NaN is considered less than any non-NaN, and -0.0 equals 0.0.
+func CompareOrdered[T Ordered](a, b T) int {
+	aNaN := a != a
+	bNaN := b != b
+	if aNaN || bNaN {
+		if aNaN && bNaN {
+			return 0
+		}
+		if aNaN {
+			return -1
+		}
+		return 1
+	}
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
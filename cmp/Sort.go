@@ -0,0 +1,315 @@
+package cmp
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// The algorithms of this file take a comparator of the elements, such as a Comparator for []interface{},
+// CompareOrdered[T] for ordered types, or any comparator of the comparator package.
+
+// Sort sorts s in ascending order of comparator. It isn't stable.
+func Sort[T any](s []T, comparator func(a, b T) int) {
+	slices.SortFunc(s, comparator)
+}
+
+// SortStable sorts s in ascending order of comparator, keeping the original order of equal elements.
+func SortStable[T any](s []T, comparator func(a, b T) int) {
+	slices.SortStableFunc(s, comparator)
+}
+
+// SortComparable sorts s with the CompareTo methods of its elements. If an element can't be compared with
+// another, it returns the first error and s is left in an unspecified order.
+func SortComparable(s []Comparable) error {
+	var err error
+	slices.SortFunc(s, compareComparables(&err))
+	return err
+}
+
+// SortStableComparable is the same as SortComparable, keeping the original order of equal elements.
+func SortStableComparable(s []Comparable) error {
+	var err error
+	slices.SortStableFunc(s, compareComparables(&err))
+	return err
+}
+
+// compareComparables returns a comparator of Comparable values recording the first error in err.
+func compareComparables(err *error) func(a, b Comparable) int {
+	return func(a, b Comparable) int {
+		if *err != nil {
+			return 0
+		}
+		r, e := a.CompareTo(b)
+		if e != nil {
+			*err = e
+		}
+		return r
+	}
+}
+
+// PartialSort rearranges s so that s[:k] holds its k smallest elements in ascending order. The order of
+// the other elements is unspecified. It runs in O(n log k).
+//
+// Example:
+//
+//   cmp.PartialSort(scores, 10, comparator.Reverse(comparator.Compare[int])) // 10 best scores first
+func PartialSort[T any](s []T, k int, comparator func(a, b T) int) {
+	k = clamp(k, len(s))
+	if k == 0 {
+		return
+	}
+	h := s[:k]
+	for i := k/2 - 1; i >= 0; i-- {
+		siftDownMax(h, i, comparator)
+	}
+	for i := k; i < len(s); i++ {
+		if comparator(s[i], h[0]) < 0 {
+			s[i], h[0] = h[0], s[i]
+			siftDownMax(h, 0, comparator)
+		}
+	}
+	slices.SortFunc(h, comparator)
+}
+
+// TopK returns the k smallest elements of s in ascending order, without modifying s. It runs in O(n log k)
+// and allocates k elements only. Reverse the comparator to get the k largest.
+func TopK[T any](s []T, k int, comparator func(a, b T) int) []T {
+	k = clamp(k, len(s))
+	h := make([]T, 0, k)
+	if k == 0 {
+		return h
+	}
+	for _, v := range s {
+		if len(h) < k {
+			h = append(h, v)
+			siftUpMax(h, len(h)-1, comparator)
+		} else if comparator(v, h[0]) < 0 {
+			h[0] = v
+			siftDownMax(h, 0, comparator)
+		}
+	}
+	slices.SortFunc(h, comparator)
+	return h
+}
+
+// NthElement rearranges s so that s[n] is the element which would be there if s were sorted, the elements
+// before it being less or equal and the elements after it greater or equal. It runs in O(n) on average.
+// It does nothing if n is out of range.
+func NthElement[T any](s []T, n int, comparator func(a, b T) int) {
+	if n < 0 || n >= len(s) {
+		return
+	}
+	lo, hi := 0, len(s)
+	// falls back to sorting if the pivots are bad too many times
+	budget := 2 * bits.Len(uint(len(s)))
+	for hi-lo > 12 {
+		if budget == 0 {
+			slices.SortFunc(s[lo:hi], comparator)
+			return
+		}
+		budget--
+		p := partition(s, lo, hi, comparator)
+		switch {
+		case n < p:
+			hi = p
+		case n > p:
+			lo = p + 1
+		default:
+			return
+		}
+	}
+	slices.SortFunc(s[lo:hi], comparator)
+}
+
+// partition partitions s[lo:hi] around a median of three pivot and returns the final position of the pivot.
+func partition[T any](s []T, lo, hi int, comparator func(a, b T) int) int {
+	mid := lo + (hi-lo)/2
+	last := hi - 1
+	if comparator(s[mid], s[lo]) < 0 {
+		s[mid], s[lo] = s[lo], s[mid]
+	}
+	if comparator(s[last], s[lo]) < 0 {
+		s[last], s[lo] = s[lo], s[last]
+	}
+	if comparator(s[last], s[mid]) < 0 {
+		s[last], s[mid] = s[mid], s[last]
+	}
+	// the median is moved to the end as the pivot
+	s[mid], s[last] = s[last], s[mid]
+	pivot := s[last]
+	i := lo
+	for j := lo; j < last; j++ {
+		if comparator(s[j], pivot) < 0 {
+			s[i], s[j] = s[j], s[i]
+			i++
+		}
+	}
+	s[i], s[last] = s[last], s[i]
+	return i
+}
+
+// LowerBound returns the index of the first element of the sorted slice s which isn't less than target,
+// or len(s) if there are none.
+func LowerBound[T any](s []T, target T, comparator func(a, b T) int) int {
+	lo, hi := 0, len(s)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if comparator(s[mid], target) < 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// UpperBound returns the index of the first element of the sorted slice s which is greater than target,
+// or len(s) if there are none.
+func UpperBound[T any](s []T, target T, comparator func(a, b T) int) int {
+	lo, hi := 0, len(s)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if comparator(s[mid], target) <= 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// BinarySearch searches target in the sorted slice s and returns the index of its first occurrence and
+// true, or the index where it would be inserted and false.
+func BinarySearch[T any](s []T, target T, comparator func(a, b T) int) (int, bool) {
+	i := LowerBound(s, target, comparator)
+	return i, i < len(s) && comparator(s[i], target) == 0
+}
+
+// EqualRange returns the bounds of the elements of the sorted slice s equal to target: s[lo:hi].
+func EqualRange[T any](s []T, target T, comparator func(a, b T) int) (lo, hi int) {
+	lo = LowerBound(s, target, comparator)
+	return lo, lo + UpperBound(s[lo:], target, comparator)
+}
+
+// Merge returns the merge of sorted sequences as a sorted sequence. Equal elements come in the order of
+// their sequences. The sequences are consumed lazily, as the result is.
+//
+// Example:
+//
+//   for line := range cmp.Merge(strings.Compare, readLines(f1), readLines(f2), readLines(f3)) {
+//       fmt.Println(line)
+//   }
+func Merge[T any](comparator func(a, b T) int, seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		h := make([]mergeHead[T], 0, len(seqs))
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			if v, ok := next(); ok {
+				h = append(h, mergeHead[T]{v, next, i})
+			}
+		}
+		less := func(a, b mergeHead[T]) bool {
+			if r := comparator(a.value, b.value); r != 0 {
+				return r < 0
+			}
+			return a.index < b.index
+		}
+		for i := len(h)/2 - 1; i >= 0; i-- {
+			siftDownMin(h, i, less)
+		}
+		for len(h) > 0 {
+			if !yield(h[0].value) {
+				return
+			}
+			if v, ok := h[0].next(); ok {
+				h[0].value = v
+			} else {
+				h[0] = h[len(h)-1]
+				h = h[:len(h)-1]
+			}
+			siftDownMin(h, 0, less)
+		}
+	}
+}
+
+// MergeSlices returns the merge of sorted slices as a new sorted slice. Equal elements come in the order
+// of their slices.
+func MergeSlices[T any](comparator func(a, b T) int, s ...[]T) []T {
+	n := 0
+	seqs := make([]iter.Seq[T], len(s))
+	for i := range s {
+		n += len(s[i])
+		seqs[i] = slices.Values(s[i])
+	}
+	out := make([]T, 0, n)
+	for v := range Merge(comparator, seqs...) {
+		out = append(out, v)
+	}
+	return out
+}
+
+type mergeHead[T any] struct {
+	value T
+	next  func() (T, bool)
+	index int // index of the sequence, to keep the merge stable
+}
+
+func clamp(k, n int) int {
+	if k < 0 {
+		return 0
+	}
+	if k > n {
+		return n
+	}
+	return k
+}
+
+// siftDownMax restores the max-heap order of h from i downwards.
+func siftDownMax[T any](h []T, i int, comparator func(a, b T) int) {
+	for {
+		child := 2*i + 1
+		if child >= len(h) {
+			return
+		}
+		if right := child + 1; right < len(h) && comparator(h[right], h[child]) > 0 {
+			child = right
+		}
+		if comparator(h[child], h[i]) <= 0 {
+			return
+		}
+		h[i], h[child] = h[child], h[i]
+		i = child
+	}
+}
+
+// siftUpMax restores the max-heap order of h from i upwards.
+func siftUpMax[T any](h []T, i int, comparator func(a, b T) int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if comparator(h[i], h[parent]) <= 0 {
+			return
+		}
+		h[i], h[parent] = h[parent], h[i]
+		i = parent
+	}
+}
+
+// siftDownMin restores the min-heap order of h from i downwards.
+func siftDownMin[T any](h []T, i int, less func(a, b T) bool) {
+	for {
+		child := 2*i + 1
+		if child >= len(h) {
+			return
+		}
+		if right := child + 1; right < len(h) && less(h[right], h[child]) {
+			child = right
+		}
+		if !less(h[child], h[i]) {
+			return
+		}
+		h[i], h[child] = h[child], h[i]
+		i = child
+	}
+}
@@ -0,0 +1,454 @@
+package collection
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// ErrIncompatibleSketch is returned when merging probabilistic structures created with different parameters.
+var ErrIncompatibleSketch = errors.New("collection: cannot merge structures with different parameters")
+
+const (
+	bloomFilterMagic         = 'B'
+	scalableBloomFilterMagic = 'S'
+	countMinSketchMagic      = 'C'
+	hyperLogLogMagic         = 'H'
+	sketchVersion            = 1
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*BloomFilter)(nil)
+	_ encoding.BinaryUnmarshaler = (*BloomFilter)(nil)
+	_ encoding.BinaryMarshaler   = (*ScalableBloomFilter)(nil)
+	_ encoding.BinaryUnmarshaler = (*ScalableBloomFilter)(nil)
+)
+
+// BloomFilter is a space-efficient set that may report false positives but never false negatives:
+// Contains returns false only if the element was never added.
+// A BloomFilter is not safe for concurrent use.
+type BloomFilter struct {
+	bits   []uint64
+	m      uint64 // number of bits
+	k      uint64 // number of hash functions
+	n      uint64 // number of elements added
+	expect uint64 // number of elements the filter was sized for
+}
+
+// NewBloomFilter is the only way to get a new, ready-to-use BloomFilter.
+//
+//   expectedItems: number of elements the filter is sized for
+//   falsePositiveRate: probability for Contains to return true for an element never added, once the
+//       filter holds expectedItems elements, between 0 and 1 exclusive
+//
+// Example:
+//
+//   seen := collection.NewBloomFilter(1000000, 0.001)
+//   if seen.TestAndAddString(id) {
+//       // probably a duplicate
+//   }
+func NewBloomFilter(expectedItems uint64, falsePositiveRate float64) *BloomFilter {
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		panic(fmt.Errorf("invalid BloomFilter false positive rate: %v", falsePositiveRate))
+	}
+	if expectedItems == 0 {
+		expectedItems = 1
+	}
+	m := math.Ceil(-float64(expectedItems) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/float64(expectedItems)*math.Ln2))
+	return newBloomFilter(uint64(m), uint64(k), expectedItems)
+}
+
+func newBloomFilter(m, k, expect uint64) *BloomFilter {
+	m = max(64, (m+63)/64*64)
+	return &BloomFilter{bits: make([]uint64, m/64), m: m, k: k, expect: expect}
+}
+
+// Add adds an element to the filter.
+func (f *BloomFilter) Add(data []byte) {
+	f.TestAndAdd(data)
+}
+
+// AddString adds a string element to the filter.
+func (f *BloomFilter) AddString(s string) {
+	f.TestAndAddString(s)
+}
+
+// Contains returns true if the element was probably added, and false if it was definitely not.
+func (f *BloomFilter) Contains(data []byte) bool {
+	return f.contains(sketchHash(data))
+}
+
+// ContainsString returns true if the string was probably added, and false if it was definitely not.
+func (f *BloomFilter) ContainsString(s string) bool {
+	return f.contains(sketchHashString(s))
+}
+
+// TestAndAdd adds an element to the filter and returns true if it was probably already there.
+func (f *BloomFilter) TestAndAdd(data []byte) bool {
+	return f.testAndAdd(sketchHash(data))
+}
+
+// TestAndAddString adds a string element to the filter and returns true if it was probably already there.
+func (f *BloomFilter) TestAndAddString(s string) bool {
+	return f.testAndAdd(sketchHashString(s))
+}
+
+func (f *BloomFilter) contains(h1, h2 uint64) bool {
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *BloomFilter) testAndAdd(h1, h2 uint64) bool {
+	present := true
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		mask := uint64(1) << (bit % 64)
+		if f.bits[bit/64]&mask == 0 {
+			present = false
+			f.bits[bit/64] |= mask
+		}
+	}
+	if !present {
+		f.n++
+	}
+	return present
+}
+
+// Count returns the number of elements added, not counting the ones reported as already present.
+func (f *BloomFilter) Count() uint64 {
+	return f.n
+}
+
+// Cap returns the number of elements the filter was sized for.
+func (f *BloomFilter) Cap() uint64 {
+	return f.expect
+}
+
+// FalsePositiveRate returns the current false positive rate of the filter, estimated from its fill ratio.
+func (f *BloomFilter) FalsePositiveRate() float64 {
+	set := 0
+	for _, w := range f.bits {
+		set += bits.OnesCount64(w)
+	}
+	return math.Pow(float64(set)/float64(f.m), float64(f.k))
+}
+
+// Merge adds the elements of other to the filter. Both filters must have been created with the same parameters.
+func (f *BloomFilter) Merge(other *BloomFilter) error {
+	if f.m != other.m || f.k != other.k {
+		return ErrIncompatibleSketch
+	}
+	for i, w := range other.bits {
+		f.bits[i] |= w
+	}
+	f.n += other.n
+	return nil
+}
+
+// Clone returns a copy of the filter.
+func (f *BloomFilter) Clone() *BloomFilter {
+	clone := *f
+	clone.bits = append([]uint64(nil), f.bits...)
+	return &clone
+}
+
+// Clear removes all the elements from the filter.
+func (f *BloomFilter) Clear() {
+	clear(f.bits)
+	f.n = 0
+}
+
+// MarshalBinary encodes the filter into a portable binary form.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	return f.appendBinary(make([]byte, 0, 2+4*8+len(f.bits)*8)), nil
+}
+
+func (f *BloomFilter) appendBinary(data []byte) []byte {
+	data = append(data, bloomFilterMagic, sketchVersion)
+	data = binary.BigEndian.AppendUint64(data, f.m)
+	data = binary.BigEndian.AppendUint64(data, f.k)
+	data = binary.BigEndian.AppendUint64(data, f.n)
+	data = binary.BigEndian.AppendUint64(data, f.expect)
+	for _, w := range f.bits {
+		data = binary.BigEndian.AppendUint64(data, w)
+	}
+	return data
+}
+
+// UnmarshalBinary replaces the filter with data produced by MarshalBinary.
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	rest, err := f.readBinary(data)
+	if err == nil && len(rest) != 0 {
+		err = errCorruptedSketch("BloomFilter")
+	}
+	return err
+}
+
+func (f *BloomFilter) readBinary(data []byte) ([]byte, error) {
+	r := sketchReader{data: data}
+	r.header(bloomFilterMagic)
+	m, k, n, expect := r.uint64(), r.uint64(), r.uint64(), r.uint64()
+	if r.err != nil || m == 0 || m%64 != 0 || k == 0 || m/8 > uint64(len(r.data)) {
+		return nil, errCorruptedSketch("BloomFilter")
+	}
+	words := make([]uint64, m/64)
+	for i := range words {
+		words[i] = r.uint64()
+	}
+	*f = BloomFilter{bits: words, m: m, k: k, n: n, expect: expect}
+	return r.data, nil
+}
+
+// ScalableBloomFilter is a Bloom filter growing with the number of elements while keeping its false
+// positive rate under a target, for when the number of elements isn't known in advance.
+// It chains Bloom filters of growing capacities and tightening false positive rates, a new one being
+// added when the last one is full.
+// A ScalableBloomFilter is not safe for concurrent use.
+type ScalableBloomFilter struct {
+	filters  []*BloomFilter
+	capacity uint64  // capacity of the first filter
+	rate     float64 // target false positive rate
+}
+
+const (
+	scalableBloomGrowth    = 2   // capacity ratio between consecutive filters
+	scalableBloomTightness = 0.5 // false positive rate ratio between consecutive filters
+)
+
+// NewScalableBloomFilter is the only way to get a new, ready-to-use ScalableBloomFilter.
+//
+//   initialCapacity: number of elements of the first filter
+//   falsePositiveRate: upper bound of the false positive rate of the whole filter, between 0 and 1 exclusive
+//
+// Example:
+//
+//   seen := collection.NewScalableBloomFilter(10000, 0.01)
+func NewScalableBloomFilter(initialCapacity uint64, falsePositiveRate float64) *ScalableBloomFilter {
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		panic(fmt.Errorf("invalid ScalableBloomFilter false positive rate: %v", falsePositiveRate))
+	}
+	f := &ScalableBloomFilter{capacity: max(1, initialCapacity), rate: falsePositiveRate}
+	f.grow()
+	return f
+}
+
+// grow adds a filter. The false positive rates of the filters form a geometric series whose sum is the target.
+func (f *ScalableBloomFilter) grow() {
+	i := len(f.filters)
+	capacity := f.capacity << min(i, 48)
+	rate := f.rate * (1 - scalableBloomTightness) * math.Pow(scalableBloomTightness, float64(i))
+	f.filters = append(f.filters, NewBloomFilter(capacity, rate))
+}
+
+// Add adds an element to the filter.
+func (f *ScalableBloomFilter) Add(data []byte) {
+	f.testAndAdd(sketchHash(data))
+}
+
+// AddString adds a string element to the filter.
+func (f *ScalableBloomFilter) AddString(s string) {
+	f.testAndAdd(sketchHashString(s))
+}
+
+// Contains returns true if the element was probably added, and false if it was definitely not.
+func (f *ScalableBloomFilter) Contains(data []byte) bool {
+	return f.contains(sketchHash(data))
+}
+
+// ContainsString returns true if the string was probably added, and false if it was definitely not.
+func (f *ScalableBloomFilter) ContainsString(s string) bool {
+	return f.contains(sketchHashString(s))
+}
+
+// TestAndAdd adds an element to the filter and returns true if it was probably already there.
+func (f *ScalableBloomFilter) TestAndAdd(data []byte) bool {
+	return f.testAndAdd(sketchHash(data))
+}
+
+// TestAndAddString adds a string element to the filter and returns true if it was probably already there.
+func (f *ScalableBloomFilter) TestAndAddString(s string) bool {
+	return f.testAndAdd(sketchHashString(s))
+}
+
+func (f *ScalableBloomFilter) contains(h1, h2 uint64) bool {
+	for i := len(f.filters) - 1; i >= 0; i-- {
+		if f.filters[i].contains(h1, h2) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *ScalableBloomFilter) testAndAdd(h1, h2 uint64) bool {
+	if f.contains(h1, h2) {
+		return true
+	}
+	last := f.filters[len(f.filters)-1]
+	if last.n >= last.expect {
+		f.grow()
+		last = f.filters[len(f.filters)-1]
+	}
+	last.testAndAdd(h1, h2)
+	return false
+}
+
+// Count returns the number of elements added, not counting the ones reported as already present.
+func (f *ScalableBloomFilter) Count() uint64 {
+	var n uint64
+	for _, filter := range f.filters {
+		n += filter.n
+	}
+	return n
+}
+
+// FalsePositiveRate returns the current false positive rate of the filter, estimated from the fill ratios
+// of its Bloom filters.
+func (f *ScalableBloomFilter) FalsePositiveRate() float64 {
+	// probability that none of the filters reports a false positive
+	none := 1.0
+	for _, filter := range f.filters {
+		none *= 1 - filter.FalsePositiveRate()
+	}
+	return 1 - none
+}
+
+// Merge adds the elements of other to the filter. Both filters must have been created with the same
+// parameters. The Bloom filters of the same rank are merged, so the false positive rate may exceed the
+// target if both filters were well filled.
+func (f *ScalableBloomFilter) Merge(other *ScalableBloomFilter) error {
+	if f.capacity != other.capacity || f.rate != other.rate {
+		return ErrIncompatibleSketch
+	}
+	for len(f.filters) < len(other.filters) {
+		f.grow()
+	}
+	for i, filter := range other.filters {
+		if err := f.filters[i].Merge(filter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clear removes all the elements from the filter.
+func (f *ScalableBloomFilter) Clear() {
+	f.filters = f.filters[:0]
+	f.grow()
+}
+
+// MarshalBinary encodes the filter into a portable binary form.
+func (f *ScalableBloomFilter) MarshalBinary() ([]byte, error) {
+	data := []byte{scalableBloomFilterMagic, sketchVersion}
+	data = binary.BigEndian.AppendUint64(data, f.capacity)
+	data = binary.BigEndian.AppendUint64(data, math.Float64bits(f.rate))
+	data = binary.BigEndian.AppendUint64(data, uint64(len(f.filters)))
+	for _, filter := range f.filters {
+		data = filter.appendBinary(data)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the filter with data produced by MarshalBinary.
+func (f *ScalableBloomFilter) UnmarshalBinary(data []byte) error {
+	r := sketchReader{data: data}
+	r.header(scalableBloomFilterMagic)
+	capacity, rate, count := r.uint64(), math.Float64frombits(r.uint64()), r.uint64()
+	if r.err != nil || capacity == 0 || !(rate > 0 && rate < 1) || count == 0 || count > uint64(len(r.data)) {
+		return errCorruptedSketch("ScalableBloomFilter")
+	}
+	filters := make([]*BloomFilter, count)
+	rest := r.data
+	for i := range filters {
+		filters[i] = &BloomFilter{}
+		var err error
+		if rest, err = filters[i].readBinary(rest); err != nil {
+			return errCorruptedSketch("ScalableBloomFilter")
+		}
+	}
+	if len(rest) != 0 {
+		return errCorruptedSketch("ScalableBloomFilter")
+	}
+	*f = ScalableBloomFilter{filters: filters, capacity: capacity, rate: rate}
+	return nil
+}
+
+// sketchHash returns two 64-bit hashes of data, combined by double hashing into as many hash functions as
+// needed. Both come from a single FNV-1a hash, the second one being a remix of the first: they aren't
+// independent, two inputs colliding on the FNV-1a hash collide on both, but they are well spread, which is
+// what double hashing needs. The hashes are stable across processes, so that serialized structures stay
+// valid.
+func sketchHash(data []byte) (uint64, uint64) {
+	h := uint64(fnvOffset64)
+	for _, b := range data {
+		h ^= uint64(b)
+		h *= fnvPrime64
+	}
+	return sketchMix(h)
+}
+
+func sketchHashString(s string) (uint64, uint64) {
+	h := uint64(fnvOffset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	return sketchMix(h)
+}
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// sketchMix spreads the FNV-1a hash with the MurmurHash3 finalizer, and derives an odd second hash from it.
+func sketchMix(h uint64) (uint64, uint64) {
+	h1 := fmix64(h)
+	h2 := fmix64(h1^0x9e3779b97f4a7c15) | 1
+	return h1, h2
+}
+
+func fmix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+func errCorruptedSketch(name string) error {
+	return fmt.Errorf("collection: corrupted %s data", name)
+}
+
+// sketchReader decodes the binary forms of the probabilistic structures, recording the first error.
+type sketchReader struct {
+	data []byte
+	err  error
+}
+
+func (r *sketchReader) header(magic byte) {
+	if len(r.data) < 2 || r.data[0] != magic || r.data[1] != sketchVersion {
+		r.err = errors.New("collection: invalid header")
+		return
+	}
+	r.data = r.data[2:]
+}
+
+func (r *sketchReader) uint64() uint64 {
+	if r.err != nil || len(r.data) < 8 {
+		r.err = errors.New("collection: truncated data")
+		return 0
+	}
+	v := binary.BigEndian.Uint64(r.data)
+	r.data = r.data[8:]
+	return v
+}
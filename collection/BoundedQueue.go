@@ -0,0 +1,167 @@
+package collection
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// NewBoundedQueue is the only way to get a new, ready-to-use BoundedQueue.
+//
+//   capacity: maximum number of elements in the queue, must be positive.
+//
+// Example:
+//
+//   bq := collection.NewBoundedQueue[*Request](1024)
+//   if !bq.Push(req) {
+//       // the queue is full, rejects the request
+//   }
+//   req, err := bq.PopWait(ctx)
+func NewBoundedQueue[T any](capacity int) *BoundedQueue[T] {
+	if capacity <= 0 {
+		panic(fmt.Errorf("invalid BoundedQueue capacity: %d", capacity))
+	}
+	bq := &BoundedQueue[T]{
+		capacity: uint64(capacity),
+		cells:    make([]boundedCell[T], capacity),
+		notEmpty: make(chan struct{}, 1),
+		notFull:  make(chan struct{}, 1),
+	}
+	for i := range bq.cells {
+		bq.cells[i].seq = uint64(i)
+	}
+	return bq
+}
+
+// BoundedQueue is a goroutine-safe, lock-free, multi-producer multi-consumer queue backed by a ring buffer
+// of a fixed capacity. It never allocates after its creation.
+type BoundedQueue[T any] struct {
+	_        [8]uint64 // padding to avoid false sharing
+	enqueue  uint64
+	_        [7]uint64
+	dequeue  uint64
+	_        [7]uint64
+	capacity uint64
+	cells    []boundedCell[T]
+	notEmpty chan struct{}
+	notFull  chan struct{}
+}
+
+type boundedCell[T any] struct {
+	// seq == position: the cell is free for the enqueue at position
+	// seq == position+1: the cell holds the value for the dequeue at position
+	seq uint64
+	val T
+}
+
+// Push inserts an element to the back of the queue.
+// Return value: true if the element was inserted and false if the queue is full.
+func (bq *BoundedQueue[T]) Push(val T) bool {
+	pos := atomic.LoadUint64(&bq.enqueue)
+	for {
+		cell := &bq.cells[pos%bq.capacity]
+		seq := atomic.LoadUint64(&cell.seq)
+		diff := int64(seq - pos)
+		if diff == 0 {
+			if atomic.CompareAndSwapUint64(&bq.enqueue, pos, pos+1) {
+				cell.val = val
+				atomic.StoreUint64(&cell.seq, pos+1)
+				notify(bq.notEmpty)
+				return true
+			}
+			pos = atomic.LoadUint64(&bq.enqueue)
+		} else if diff < 0 {
+			return false
+		} else {
+			pos = atomic.LoadUint64(&bq.enqueue)
+		}
+	}
+}
+
+// PushWait is the same as Push, except that it waits for a free slot if the queue is full.
+// It returns ctx's error if ctx is done before the element could be inserted.
+func (bq *BoundedQueue[T]) PushWait(ctx context.Context, val T) error {
+	for {
+		if bq.Push(val) {
+			if bq.Len() < bq.Cap() {
+				notify(bq.notFull)
+			}
+			return nil
+		}
+		select {
+		case <-bq.notFull:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Pop returns (and removes) an element from the front of the queue and true,
+// or the zero value and false if the queue is empty.
+func (bq *BoundedQueue[T]) Pop() (T, bool) {
+	pos := atomic.LoadUint64(&bq.dequeue)
+	for {
+		cell := &bq.cells[pos%bq.capacity]
+		seq := atomic.LoadUint64(&cell.seq)
+		diff := int64(seq - (pos + 1))
+		if diff == 0 {
+			if atomic.CompareAndSwapUint64(&bq.dequeue, pos, pos+1) {
+				val := cell.val
+				var zero T
+				cell.val = zero
+				atomic.StoreUint64(&cell.seq, pos+bq.capacity)
+				notify(bq.notFull)
+				return val, true
+			}
+			pos = atomic.LoadUint64(&bq.dequeue)
+		} else if diff < 0 {
+			var zero T
+			return zero, false
+		} else {
+			pos = atomic.LoadUint64(&bq.dequeue)
+		}
+	}
+}
+
+// PopWait is the same as Pop, except that it waits for an element if the queue is empty.
+// It returns ctx's error if ctx is done before an element could be popped.
+func (bq *BoundedQueue[T]) PopWait(ctx context.Context) (T, error) {
+	for {
+		if val, ok := bq.Pop(); ok {
+			if bq.Len() > 0 {
+				notify(bq.notEmpty)
+			}
+			return val, nil
+		}
+		select {
+		case <-bq.notEmpty:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Len returns the number of elements in the queue.
+// It's only a snapshot when the queue is used concurrently.
+func (bq *BoundedQueue[T]) Len() int {
+	for {
+		dequeue := atomic.LoadUint64(&bq.dequeue)
+		enqueue := atomic.LoadUint64(&bq.enqueue)
+		if dequeue == atomic.LoadUint64(&bq.dequeue) {
+			if enqueue < dequeue {
+				return 0
+			}
+			n := enqueue - dequeue
+			if n > bq.capacity {
+				n = bq.capacity
+			}
+			return int(n)
+		}
+	}
+}
+
+// Cap returns the capacity of the queue.
+func (bq *BoundedQueue[T]) Cap() int {
+	return int(bq.capacity)
+}
@@ -0,0 +1,138 @@
+package collection
+
+import (
+	"fmt"
+	"iter"
+	"net/netip"
+)
+
+// CIDRTree is a map from IP prefixes to values finding the most specific prefix containing an address,
+// such as for routing tables and IP allow lists. IPv4 and IPv6 prefixes can be mixed, IPv4-mapped IPv6
+// addresses are looked up as IPv4 addresses.
+// It's a RadixTree whose keys are the bits of the prefixes.
+// A CIDRTree is not safe for concurrent use.
+type CIDRTree[V any] struct {
+	tree *RadixTree[cidrEntry[V]]
+}
+
+type cidrEntry[V any] struct {
+	prefix netip.Prefix
+	value  V
+}
+
+// NewCIDRTree is the only way to get a new, ready-to-use CIDRTree.
+//
+// Example:
+//
+//   zones := collection.NewCIDRTree[string]()
+//   zones.PutCIDR("10.0.0.0/8", "internal")
+//   zones.PutCIDR("10.1.0.0/16", "office")
+//   prefix, zone, ok := zones.Lookup(netip.MustParseAddr("10.1.2.3")) // 10.1.0.0/16, "office", true
+func NewCIDRTree[V any]() *CIDRTree[V] {
+	return &CIDRTree[V]{NewRadixTree[cidrEntry[V]]()}
+}
+
+// Len returns the number of prefixes of the tree.
+func (t *CIDRTree[V]) Len() int {
+	return t.tree.Len()
+}
+
+// Put associates the prefix to value and returns true if the prefix was added, false if its value was replaced.
+// The host bits of the prefix are ignored: 10.1.2.3/8 is the same prefix as 10.0.0.0/8.
+// Invalid prefixes are ignored.
+func (t *CIDRTree[V]) Put(prefix netip.Prefix, value V) bool {
+	if !prefix.IsValid() {
+		return false
+	}
+	prefix = prefix.Masked()
+	return t.tree.Put(cidrKey(prefix.Addr(), prefix.Bits()), cidrEntry[V]{prefix, value})
+}
+
+// PutCIDR parses a prefix in CIDR notation, such as "192.168.0.0/16", and associates it to value.
+func (t *CIDRTree[V]) PutCIDR(cidr string, value V) error {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return fmt.Errorf("collection: %w", err)
+	}
+	t.Put(prefix, value)
+	return nil
+}
+
+// Get returns the value of exactly this prefix and true, or the zero value and false if it isn't found.
+func (t *CIDRTree[V]) Get(prefix netip.Prefix) (V, bool) {
+	if !prefix.IsValid() {
+		var zero V
+		return zero, false
+	}
+	e, ok := t.tree.Get(cidrKey(prefix.Addr(), prefix.Bits()))
+	return e.value, ok
+}
+
+// Delete removes the prefix and returns true, or returns false if it wasn't found.
+func (t *CIDRTree[V]) Delete(prefix netip.Prefix) bool {
+	return prefix.IsValid() && t.tree.Delete(cidrKey(prefix.Addr(), prefix.Bits()))
+}
+
+// Lookup returns the most specific prefix containing addr, and its value.
+// It returns false if no prefix contains addr.
+func (t *CIDRTree[V]) Lookup(addr netip.Addr) (netip.Prefix, V, bool) {
+	if !addr.IsValid() {
+		var zero V
+		return netip.Prefix{}, zero, false
+	}
+	addr = addr.Unmap()
+	_, e, ok := t.tree.LongestPrefix(cidrKey(addr, addr.BitLen()))
+	return e.prefix, e.value, ok
+}
+
+// Contains returns true if a prefix of the tree contains addr.
+func (t *CIDRTree[V]) Contains(addr netip.Addr) bool {
+	_, _, ok := t.Lookup(addr)
+	return ok
+}
+
+// All returns an iterator over the prefixes of the tree and their values, IPv4 prefixes first, in ascending
+// order of addresses, a prefix coming before the more specific ones it contains.
+func (t *CIDRTree[V]) All() iter.Seq2[netip.Prefix, V] {
+	return t.within("")
+}
+
+// Within returns an iterator over the prefixes of the tree contained in prefix, including itself, in the
+// order of All.
+func (t *CIDRTree[V]) Within(prefix netip.Prefix) iter.Seq2[netip.Prefix, V] {
+	if !prefix.IsValid() {
+		return func(yield func(netip.Prefix, V) bool) {}
+	}
+	return t.within(cidrKey(prefix.Addr(), prefix.Bits()))
+}
+
+func (t *CIDRTree[V]) within(key string) iter.Seq2[netip.Prefix, V] {
+	return func(yield func(netip.Prefix, V) bool) {
+		for _, e := range t.tree.Prefix(key) {
+			if !yield(e.prefix, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Clear removes all the prefixes from the tree.
+func (t *CIDRTree[V]) Clear() {
+	t.tree.Clear()
+}
+
+// cidrKey returns the key of the first bits of addr: the address family followed by a byte per bit, so that
+// the keys are sorted in address order.
+func cidrKey(addr netip.Addr, bits int) string {
+	key := make([]byte, 1, 1+bits)
+	if addr.Is4() {
+		key[0] = '4'
+	} else {
+		key[0] = '6'
+	}
+	b := addr.AsSlice()
+	for i := 0; i < bits; i++ {
+		key = append(key, '0'+(b[i/8]>>(7-i%8))&1)
+	}
+	return string(key)
+}
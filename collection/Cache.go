@@ -0,0 +1,411 @@
+package collection
+
+import (
+	"slices"
+	"time"
+
+	"github.com/cnfree/common/cmp"
+)
+
+// EvictionPolicy decides which entry of a full Cache is evicted.
+type EvictionPolicy int
+
+const (
+	// LRU evicts the least recently used entry.
+	LRU EvictionPolicy = iota
+	// LFU evicts the least frequently used entry, the least recently inserted one among equals.
+	LFU
+	// FIFO evicts the oldest inserted entry.
+	FIFO
+)
+
+// EvictionReason tells why an entry left a Cache.
+type EvictionReason int
+
+const (
+	// EvictedByCapacity means the entry was evicted to respect MaxEntries or MaxCost.
+	EvictedByCapacity EvictionReason = iota
+	// EvictedByExpiration means the TTL of the entry elapsed.
+	EvictedByExpiration
+	// EvictedByRemoval means the entry was removed by Remove or Clear.
+	EvictedByRemoval
+)
+
+// CacheOptions configures a Cache. Zero fields mean no limit or no callback.
+type CacheOptions[K, V any] struct {
+	Policy EvictionPolicy
+	// MaxEntries is the maximum number of entries of the cache.
+	MaxEntries int
+	// MaxCost is the maximum total cost of the entries of the cache.
+	MaxCost int64
+	// Cost returns the cost of an entry, typically its size in bytes. nil means a cost of 1 per entry.
+	Cost func(key K, value V) int64
+	// TTL is the time to live of the entries added by Put.
+	TTL time.Duration
+	// OnEvict, if not nil, is called whenever an entry leaves the cache, except when it's overwritten by Put.
+	OnEvict func(key K, value V, reason EvictionReason)
+}
+
+// CacheStats holds the counters of a Cache.
+type CacheStats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64 // entries evicted by capacity
+	Expirations uint64 // entries evicted by expiration
+}
+
+// HitRatio returns the ratio of hits among all the lookups, or 0 if there was no lookup.
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+func (s *CacheStats) add(o CacheStats) {
+	s.Hits += o.Hits
+	s.Misses += o.Misses
+	s.Evictions += o.Evictions
+	s.Expirations += o.Expirations
+}
+
+// NewCache is the only way to get a new, ready-to-use Cache with a custom key comparator.
+//
+// Example:
+//
+//   cache := collection.NewCache[string, []byte](strings.Compare, collection.CacheOptions[string, []byte]{
+//       Policy:  collection.LRU,
+//       MaxCost: 64 << 20,
+//       Cost:    func(key string, value []byte) int64 { return int64(len(value)) },
+//       TTL:     10 * time.Minute,
+//   })
+func NewCache[K, V any](comparator func(a, b K) int, options CacheOptions[K, V]) *Cache[K, V] {
+	return &Cache[K, V]{
+		entries:  NewLinkedOrderedMap[K, *cacheEntry[K, V]](comparator),
+		options:  options,
+		buckets:  make(map[uint64]*lfuBucket[K, V]),
+		minCount: 0,
+	}
+}
+
+// NewDefaultCache returns a new, ready-to-use Cache whose keys are compared with their natural order.
+func NewDefaultCache[K cmp.Ordered, V any](options CacheOptions[K, V]) *Cache[K, V] {
+	return NewCache[K, V](cmp.CompareOrdered[K], options)
+}
+
+// Cache is a bounded key-value cache with LRU, LFU or FIFO eviction and per-entry TTL.
+// The recency and insertion orders are kept by the insertion ordered links of a LinkedOrderedMap.
+// Cache is not goroutine-safe, see ShardedCache for a concurrent variant.
+type Cache[K, V any] struct {
+	entries *LinkedOrderedMap[K, *cacheEntry[K, V]]
+	options CacheOptions[K, V]
+	cost    int64
+	stats   CacheStats
+
+	// LFU only: entries grouped by access count, each group in insertion order
+	buckets  map[uint64]*lfuBucket[K, V]
+	minCount uint64
+}
+
+type cacheEntry[K, V any] struct {
+	key      K
+	value    V
+	cost     int64
+	expireAt time.Time // zero if the entry never expires
+
+	// LFU only
+	count      uint64
+	prev, next *cacheEntry[K, V]
+}
+
+type lfuBucket[K, V any] struct {
+	head, tail *cacheEntry[K, V]
+}
+
+// Get returns the value of the key and true if the key is cached and not expired.
+// It counts as an access of the entry for the eviction policy.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	node := c.entries.search(key)
+	if node == nil {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	e := node.v
+	if e.expired(time.Now()) {
+		c.remove(e, EvictedByExpiration)
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	c.touch(node)
+	return e.value, true
+}
+
+// Peek is the same as Get, except that it neither counts as an access nor updates the stats.
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	e, ok := c.entries.Get(key)
+	if !ok || e.expired(time.Now()) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Contains returns true if the key is cached and not expired, without counting as an access.
+func (c *Cache[K, V]) Contains(key K) bool {
+	_, ok := c.Peek(key)
+	return ok
+}
+
+// Put adds or updates an entry with the default TTL of the cache, then evicts entries if the cache
+// exceeds its capacity.
+func (c *Cache[K, V]) Put(key K, value V) {
+	c.PutWithTTL(key, value, c.options.TTL)
+}
+
+// PutWithTTL is the same as Put with a specific TTL. A ttl of 0 means the entry never expires.
+func (c *Cache[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	cost := int64(1)
+	if c.options.Cost != nil {
+		cost = c.options.Cost(key, value)
+	}
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+
+	if node := c.entries.search(key); node != nil {
+		e := node.v
+		c.cost += cost - e.cost
+		e.key = key
+		e.value = value
+		e.cost = cost
+		e.expireAt = expireAt
+		c.touch(node)
+	} else {
+		// makes room before adding the entry, otherwise LFU would evict it first as it has the lowest count
+		c.evict(1, cost)
+		e := &cacheEntry[K, V]{key: key, value: value, cost: cost, expireAt: expireAt}
+		c.entries.Put(key, e)
+		c.cost += cost
+		if c.options.Policy == LFU {
+			c.lfuAdd(e)
+		}
+	}
+	c.evict(0, 0)
+}
+
+// Remove removes the entry of the key.
+// Return value: true if the key was cached.
+func (c *Cache[K, V]) Remove(key K) bool {
+	e, ok := c.entries.Get(key)
+	if !ok {
+		return false
+	}
+	c.remove(e, EvictedByRemoval)
+	return true
+}
+
+// RemoveExpired removes all the expired entries.
+// Expired entries are otherwise only removed when they are looked up or evicted by capacity.
+// Return value: the number of removed entries.
+func (c *Cache[K, V]) RemoveExpired() int {
+	now := time.Now()
+	var expired []*cacheEntry[K, V]
+	for _, e := range c.entries.InsertionOrder() {
+		if e.expired(now) {
+			expired = append(expired, e)
+		}
+	}
+	for _, e := range expired {
+		c.remove(e, EvictedByExpiration)
+	}
+	return len(expired)
+}
+
+// Clear removes all the entries. The stats are kept.
+func (c *Cache[K, V]) Clear() {
+	if c.options.OnEvict != nil {
+		for _, e := range c.entries.InsertionOrder() {
+			c.options.OnEvict(e.key, e.value, EvictedByRemoval)
+		}
+	}
+	c.entries.Clear()
+	c.cost = 0
+	c.buckets = make(map[uint64]*lfuBucket[K, V])
+	c.minCount = 0
+}
+
+// Len returns the number of entries, including the expired ones not removed yet.
+func (c *Cache[K, V]) Len() int {
+	return c.entries.Size()
+}
+
+// Cost returns the total cost of the entries.
+func (c *Cache[K, V]) Cost() int64 {
+	return c.cost
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (c *Cache[K, V]) Stats() CacheStats {
+	return c.stats
+}
+
+// Keys returns the keys of the cache, from the next to be evicted to the last, ignoring TTLs.
+func (c *Cache[K, V]) Keys() []K {
+	keys := make([]K, 0, c.entries.Size())
+	if c.options.Policy == LFU {
+		// the counts are sparse, a single entry may have been accessed billions of times
+		counts := make([]uint64, 0, len(c.buckets))
+		for count := range c.buckets {
+			counts = append(counts, count)
+		}
+		slices.Sort(counts)
+		for _, count := range counts {
+			for e := c.buckets[count].head; e != nil; e = e.next {
+				keys = append(keys, e.key)
+			}
+		}
+		return keys
+	}
+	for k := range c.entries.InsertionOrder() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// touch records an access of the entry held by node.
+func (c *Cache[K, V]) touch(node *lrbtNode[K, *cacheEntry[K, V]]) {
+	switch c.options.Policy {
+	case LRU:
+		c.entries.moveToBack(node)
+	case LFU:
+		c.lfuIncrement(node.v)
+	}
+}
+
+// evict removes entries until the cache respects its capacity, leaving room for n more entries of a
+// total cost of cost.
+func (c *Cache[K, V]) evict(n int, cost int64) {
+	for c.entries.Size() > 0 &&
+		((c.options.MaxEntries > 0 && c.entries.Size()+n > c.options.MaxEntries) ||
+			(c.options.MaxCost > 0 && c.cost+cost > c.options.MaxCost)) {
+		victim := c.victim()
+		if victim.expired(time.Now()) {
+			c.remove(victim, EvictedByExpiration)
+		} else {
+			c.remove(victim, EvictedByCapacity)
+		}
+	}
+}
+
+// victim returns the next entry to be evicted according to the policy. The cache mustn't be empty.
+func (c *Cache[K, V]) victim() *cacheEntry[K, V] {
+	if c.options.Policy == LFU {
+		return c.buckets[c.minCount].head
+	}
+	// both LRU and FIFO evict the head of the insertion ordered list,
+	// LRU moves the accessed entries to its back
+	return c.entries.head.v
+}
+
+func (c *Cache[K, V]) remove(e *cacheEntry[K, V], reason EvictionReason) {
+	c.entries.Erase(e.key)
+	c.cost -= e.cost
+	if c.options.Policy == LFU {
+		c.lfuRemove(e)
+	}
+	switch reason {
+	case EvictedByCapacity:
+		c.stats.Evictions++
+	case EvictedByExpiration:
+		c.stats.Expirations++
+	}
+	if c.options.OnEvict != nil {
+		c.options.OnEvict(e.key, e.value, reason)
+	}
+}
+
+func (c *Cache[K, V]) lfuAdd(e *cacheEntry[K, V]) {
+	e.count = 1
+	c.bucket(1).pushBack(e)
+	c.minCount = 1
+}
+
+func (c *Cache[K, V]) lfuIncrement(e *cacheEntry[K, V]) {
+	b := c.buckets[e.count]
+	b.remove(e)
+	if b.head == nil {
+		delete(c.buckets, e.count)
+		if c.minCount == e.count {
+			c.minCount++
+		}
+	}
+	e.count++
+	c.bucket(e.count).pushBack(e)
+}
+
+func (c *Cache[K, V]) lfuRemove(e *cacheEntry[K, V]) {
+	b := c.buckets[e.count]
+	b.remove(e)
+	if b.head != nil {
+		return
+	}
+	delete(c.buckets, e.count)
+	if c.minCount != e.count {
+		return
+	}
+	if len(c.buckets) == 0 {
+		c.minCount = 0
+		return
+	}
+	// removing is rare compared to accessing, a scan of the counts is fine
+	c.minCount = 0
+	for count := range c.buckets {
+		if c.minCount == 0 || count < c.minCount {
+			c.minCount = count
+		}
+	}
+}
+
+func (c *Cache[K, V]) bucket(count uint64) *lfuBucket[K, V] {
+	b, ok := c.buckets[count]
+	if !ok {
+		b = &lfuBucket[K, V]{}
+		c.buckets[count] = b
+	}
+	return b
+}
+
+func (e *cacheEntry[K, V]) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && now.After(e.expireAt)
+}
+
+func (b *lfuBucket[K, V]) pushBack(e *cacheEntry[K, V]) {
+	e.prev = b.tail
+	e.next = nil
+	if b.tail != nil {
+		b.tail.next = e
+	} else {
+		b.head = e
+	}
+	b.tail = e
+}
+
+func (b *lfuBucket[K, V]) remove(e *cacheEntry[K, V]) {
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		b.head = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		b.tail = e.prev
+	}
+	e.prev = nil
+	e.next = nil
+}
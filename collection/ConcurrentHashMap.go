@@ -0,0 +1,214 @@
+package collection
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+const defaultConcurrentHashMapShards = 32
+
+// NewConcurrentHashMap is the only way to get a new, ready-to-use ConcurrentHashMap.
+//
+//   shards: number of independently locked segments. 0 defaults to 32.
+//
+// Example:
+//
+//   hits := collection.NewConcurrentHashMap[string, int](0)
+//   hits.Compute(path, func(n int, found bool) (int, bool) { return n + 1, true })
+func NewConcurrentHashMap[K comparable, V any](shards int) *ConcurrentHashMap[K, V] {
+	if shards <= 0 {
+		shards = defaultConcurrentHashMapShards
+	}
+	m := &ConcurrentHashMap[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]hashMapShard[K, V], shards),
+	}
+	for i := range m.shards {
+		m.shards[i].m = make(map[K]V)
+	}
+	return m
+}
+
+// ConcurrentHashMap is a goroutine-safe hash map using lock striping: the keys are spread by hash over
+// several Go maps, each guarded by its own sync.RWMutex, so that operations on different shards
+// don't contend. The compound operations are atomic for a given key.
+type ConcurrentHashMap[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []hashMapShard[K, V]
+}
+
+type hashMapShard[K comparable, V any] struct {
+	lock sync.RWMutex
+	m    map[K]V
+	_    [32]byte // padding to avoid false sharing
+}
+
+// Get returns the value of the key and true if the key is found.
+func (m *ConcurrentHashMap[K, V]) Get(key K) (V, bool) {
+	s := m.shard(key)
+	s.lock.RLock()
+	v, ok := s.m[key]
+	s.lock.RUnlock()
+	return v, ok
+}
+
+// Put inserts or updates an element.
+func (m *ConcurrentHashMap[K, V]) Put(key K, value V) {
+	s := m.shard(key)
+	s.lock.Lock()
+	s.m[key] = value
+	s.lock.Unlock()
+}
+
+// Remove removes the element of the key.
+// Return value: the removed value and true if the key was found.
+func (m *ConcurrentHashMap[K, V]) Remove(key K) (V, bool) {
+	s := m.shard(key)
+	s.lock.Lock()
+	v, ok := s.m[key]
+	if ok {
+		delete(s.m, key)
+	}
+	s.lock.Unlock()
+	return v, ok
+}
+
+// PutIfAbsent inserts the element only if the key is not found.
+// Return value: the current value of the key and true if the insertion took place.
+func (m *ConcurrentHashMap[K, V]) PutIfAbsent(key K, value V) (V, bool) {
+	s := m.shard(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if current, ok := s.m[key]; ok {
+		return current, false
+	}
+	s.m[key] = value
+	return value, true
+}
+
+// ComputeIfAbsent returns the value of the key, or atomically inserts and returns the result of fn if the
+// key is not found. fn is called with the lock of the shard held, it mustn't access the map.
+func (m *ConcurrentHashMap[K, V]) ComputeIfAbsent(key K, fn func(K) V) V {
+	s := m.shard(key)
+	s.lock.RLock()
+	v, ok := s.m[key]
+	s.lock.RUnlock()
+	if ok {
+		return v
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v
+	}
+	v = fn(key)
+	s.m[key] = v
+	return v
+}
+
+// ComputeIfPresent atomically replaces the value of the key by the result of fn if the key is found.
+// The element is removed if fn returns false. fn is called with the lock of the shard held,
+// it mustn't access the map.
+// Return value: the new value and whether the key is present afterwards.
+func (m *ConcurrentHashMap[K, V]) ComputeIfPresent(key K, fn func(key K, value V) (V, bool)) (V, bool) {
+	s := m.shard(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	v, ok := s.m[key]
+	if !ok {
+		return v, false
+	}
+	v, keep := fn(key, v)
+	if keep {
+		s.m[key] = v
+	} else {
+		delete(s.m, key)
+	}
+	return v, keep
+}
+
+// Compute atomically replaces the value of the key by the result of fn, which receives the current value
+// and whether it was found. The element is removed if fn returns false. fn is called with the lock of the
+// shard held, it mustn't access the map.
+// Return value: the new value and whether the key is present afterwards.
+func (m *ConcurrentHashMap[K, V]) Compute(key K, fn func(value V, found bool) (V, bool)) (V, bool) {
+	s := m.shard(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	v, found := s.m[key]
+	v, keep := fn(v, found)
+	if keep {
+		s.m[key] = v
+	} else if found {
+		delete(s.m, key)
+	}
+	return v, keep
+}
+
+// CompareAndSwap replaces the value of the key by newValue only if its current value is equal to oldValue
+// according to equal.
+// Return value: true if the swap took place.
+func (m *ConcurrentHashMap[K, V]) CompareAndSwap(key K, oldValue, newValue V, equal func(a, b V) bool) bool {
+	s := m.shard(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	current, ok := s.m[key]
+	if !ok || !equal(current, oldValue) {
+		return false
+	}
+	s.m[key] = newValue
+	return true
+}
+
+// Len returns the number of elements. It's only a snapshot when the map is used concurrently.
+func (m *ConcurrentHashMap[K, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.lock.RLock()
+		n += len(s.m)
+		s.lock.RUnlock()
+	}
+	return n
+}
+
+// Range calls fn for every element, shard after shard with the read lock of the shard held,
+// until fn returns false. fn mustn't modify the map. The order is unspecified.
+func (m *ConcurrentHashMap[K, V]) Range(fn func(key K, value V) bool) {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.lock.RLock()
+		for k, v := range s.m {
+			if !fn(k, v) {
+				s.lock.RUnlock()
+				return
+			}
+		}
+		s.lock.RUnlock()
+	}
+}
+
+// Keys returns a snapshot of the keys, in unspecified order.
+func (m *ConcurrentHashMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	m.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Clear removes all elements.
+func (m *ConcurrentHashMap[K, V]) Clear() {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.lock.Lock()
+		s.m = make(map[K]V)
+		s.lock.Unlock()
+	}
+}
+
+func (m *ConcurrentHashMap[K, V]) shard(key K) *hashMapShard[K, V] {
+	return &m.shards[maphash.Comparable(m.seed, key)%uint64(len(m.shards))]
+}
@@ -0,0 +1,133 @@
+package collection
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*CountMinSketch)(nil)
+	_ encoding.BinaryUnmarshaler = (*CountMinSketch)(nil)
+)
+
+// CountMinSketch estimates the frequencies of elements in a stream with a fixed amount of memory.
+// The estimates are never below the real counts, and exceed them by at most epsilon times the total count
+// with a probability of 1 - delta.
+// A CountMinSketch is not safe for concurrent use.
+type CountMinSketch struct {
+	counters []uint64 // depth rows of width counters
+	width    uint64
+	depth    uint64
+	total    uint64
+}
+
+// NewCountMinSketch is the only way to get a new, ready-to-use CountMinSketch.
+//
+//   epsilon: maximum overestimation, relative to the total count, between 0 and 1 exclusive
+//   delta: probability for an estimate to exceed this bound, between 0 and 1 exclusive
+//
+// Example:
+//
+//   cms := collection.NewCountMinSketch(0.001, 0.01)
+//   cms.AddString(path, 1)
+//   hits := cms.EstimateString(path)
+func NewCountMinSketch(epsilon, delta float64) *CountMinSketch {
+	if epsilon <= 0 || epsilon >= 1 || delta <= 0 || delta >= 1 {
+		panic(fmt.Errorf("invalid CountMinSketch parameters: epsilon %v, delta %v", epsilon, delta))
+	}
+	width := uint64(math.Ceil(math.E / epsilon))
+	depth := uint64(math.Ceil(math.Log(1 / delta)))
+	return &CountMinSketch{counters: make([]uint64, width*depth), width: width, depth: depth}
+}
+
+// Add adds count occurrences of an element.
+func (s *CountMinSketch) Add(data []byte, count uint64) {
+	h1, h2 := sketchHash(data)
+	s.add(h1, h2, count)
+}
+
+// AddString adds count occurrences of a string element.
+func (s *CountMinSketch) AddString(str string, count uint64) {
+	h1, h2 := sketchHashString(str)
+	s.add(h1, h2, count)
+}
+
+func (s *CountMinSketch) add(h1, h2, count uint64) {
+	for i := uint64(0); i < s.depth; i++ {
+		s.counters[i*s.width+(h1+i*h2)%s.width] += count
+	}
+	s.total += count
+}
+
+// Estimate returns the estimated number of occurrences of an element.
+func (s *CountMinSketch) Estimate(data []byte) uint64 {
+	return s.estimate(sketchHash(data))
+}
+
+// EstimateString returns the estimated number of occurrences of a string element.
+func (s *CountMinSketch) EstimateString(str string) uint64 {
+	return s.estimate(sketchHashString(str))
+}
+
+func (s *CountMinSketch) estimate(h1, h2 uint64) uint64 {
+	estimate := uint64(math.MaxUint64)
+	for i := uint64(0); i < s.depth; i++ {
+		estimate = min(estimate, s.counters[i*s.width+(h1+i*h2)%s.width])
+	}
+	return estimate
+}
+
+// Total returns the total number of occurrences added.
+func (s *CountMinSketch) Total() uint64 {
+	return s.total
+}
+
+// Merge adds the counts of other to the sketch. Both sketches must have been created with the same parameters.
+func (s *CountMinSketch) Merge(other *CountMinSketch) error {
+	if s.width != other.width || s.depth != other.depth {
+		return ErrIncompatibleSketch
+	}
+	for i, c := range other.counters {
+		s.counters[i] += c
+	}
+	s.total += other.total
+	return nil
+}
+
+// Clear resets all the counts.
+func (s *CountMinSketch) Clear() {
+	clear(s.counters)
+	s.total = 0
+}
+
+// MarshalBinary encodes the sketch into a portable binary form.
+func (s *CountMinSketch) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 2+3*8+len(s.counters)*8)
+	data = append(data, countMinSketchMagic, sketchVersion)
+	data = binary.BigEndian.AppendUint64(data, s.width)
+	data = binary.BigEndian.AppendUint64(data, s.depth)
+	data = binary.BigEndian.AppendUint64(data, s.total)
+	for _, c := range s.counters {
+		data = binary.BigEndian.AppendUint64(data, c)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the sketch with data produced by MarshalBinary.
+func (s *CountMinSketch) UnmarshalBinary(data []byte) error {
+	r := sketchReader{data: data}
+	r.header(countMinSketchMagic)
+	width, depth, total := r.uint64(), r.uint64(), r.uint64()
+	if r.err != nil || width == 0 || depth == 0 || width > uint64(len(r.data)) || depth > uint64(len(r.data)) ||
+		uint64(len(r.data)) != width*depth*8 {
+		return errCorruptedSketch("CountMinSketch")
+	}
+	counters := make([]uint64, width*depth)
+	for i := range counters {
+		counters[i] = r.uint64()
+	}
+	*s = CountMinSketch{counters: counters, width: width, depth: depth, total: total}
+	return nil
+}
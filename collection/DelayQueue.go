@@ -0,0 +1,157 @@
+package collection
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cnfree/common/cmp"
+)
+
+// DelayQueue is a goroutine-safe queue whose elements can only be taken once their scheduled time has come.
+// The elements are taken in the order of their scheduled times.
+type DelayQueue[T any] struct {
+	mutex    sync.Mutex
+	pq       *PriorityQueue[*delayed[T]]
+	seq      uint64
+	notEmpty chan struct{} // signaled when the head may have changed
+}
+
+// DelayHandle is a reference to an element of a DelayQueue, for cancelling it.
+type DelayHandle[T any] struct {
+	h *PriorityQueueHandle[*delayed[T]]
+}
+
+type delayed[T any] struct {
+	value T
+	at    time.Time
+	seq   uint64 // keeps the insertion order of elements scheduled at the same time
+}
+
+// NewDelayQueue is the only way to get a new, ready-to-use DelayQueue.
+//
+// Example:
+//
+//   dq := collection.NewDelayQueue[string]()
+//   dq.PutDelay("retry", 5*time.Second)
+//   v, err := dq.Take(ctx) // returns "retry" in 5 seconds
+func NewDelayQueue[T any]() *DelayQueue[T] {
+	return &DelayQueue[T]{
+		pq: NewPriorityQueue(func(a, b *delayed[T]) int {
+			if c := a.at.Compare(b.at); c != 0 {
+				return c
+			}
+			return cmp.CompareOrdered(a.seq, b.seq)
+		}),
+		notEmpty: make(chan struct{}, 1),
+	}
+}
+
+// Put adds an element available from the given time.
+func (dq *DelayQueue[T]) Put(value T, at time.Time) DelayHandle[T] {
+	dq.mutex.Lock()
+	dq.seq++
+	h := dq.pq.Push(&delayed[T]{value: value, at: at, seq: dq.seq})
+	dq.mutex.Unlock()
+	notify(dq.notEmpty)
+	return DelayHandle[T]{h}
+}
+
+// PutDelay adds an element available after the given delay.
+func (dq *DelayQueue[T]) PutDelay(value T, delay time.Duration) DelayHandle[T] {
+	return dq.Put(value, time.Now().Add(delay))
+}
+
+// Remove removes the element referenced by h and returns true, or returns false if it was already taken or removed.
+func (dq *DelayQueue[T]) Remove(h DelayHandle[T]) bool {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	_, ok := dq.pq.Remove(h.h)
+	return ok
+}
+
+// Poll removes and returns the first element if its time has come, or returns the zero value and false otherwise.
+func (dq *DelayQueue[T]) Poll() (T, bool) {
+	value, _, ok := dq.poll(time.Now())
+	return value, ok
+}
+
+// Take removes and returns the first element, waiting for its time to come.
+// It returns ctx's error if ctx is done before an element is available.
+func (dq *DelayQueue[T]) Take(ctx context.Context) (T, error) {
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	for {
+		value, wait, ok := dq.poll(time.Now())
+		if ok {
+			if dq.Len() > 0 {
+				// passes the notification on to another waiter
+				notify(dq.notEmpty)
+			}
+			return value, nil
+		}
+		var expired <-chan time.Time
+		if wait > 0 {
+			if timer == nil {
+				timer = time.NewTimer(wait)
+			} else {
+				timer.Reset(wait)
+			}
+			expired = timer.C
+		}
+		select {
+		case <-dq.notEmpty:
+		case <-expired:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// poll pops the first element if its time has come, otherwise it returns how long to wait for it, or 0
+// if the queue is empty.
+func (dq *DelayQueue[T]) poll(now time.Time) (T, time.Duration, bool) {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	var zero T
+	head, ok := dq.pq.Peek()
+	if !ok {
+		return zero, 0, false
+	}
+	if wait := head.at.Sub(now); wait > 0 {
+		return zero, wait, false
+	}
+	dq.pq.Pop()
+	return head.value, 0, true
+}
+
+// Peek returns the first element and its scheduled time without removing it, or false if the queue is empty.
+func (dq *DelayQueue[T]) Peek() (T, time.Time, bool) {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	head, ok := dq.pq.Peek()
+	if !ok {
+		var zero T
+		return zero, time.Time{}, false
+	}
+	return head.value, head.at, true
+}
+
+// Len returns the number of elements in the queue, including the ones whose time hasn't come yet.
+func (dq *DelayQueue[T]) Len() int {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	return dq.pq.Len()
+}
+
+// Clear removes all the elements from the queue.
+func (dq *DelayQueue[T]) Clear() {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	dq.pq.Clear()
+}
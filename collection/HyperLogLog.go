@@ -0,0 +1,127 @@
+package collection
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*HyperLogLog)(nil)
+	_ encoding.BinaryUnmarshaler = (*HyperLogLog)(nil)
+)
+
+// HyperLogLog estimates the number of distinct elements of a stream with 2^precision bytes of memory.
+// The standard error of the estimate is about 1.04 / sqrt(2^precision), 0.8% for a precision of 14.
+// A HyperLogLog is not safe for concurrent use.
+type HyperLogLog struct {
+	registers []uint8
+	precision uint8
+}
+
+// NewHyperLogLog is the only way to get a new, ready-to-use HyperLogLog.
+//
+//   precision: number of bits of the hashes used to select a register, between 4 and 18
+//
+// Example:
+//
+//   visitors := collection.NewHyperLogLog(14)
+//   visitors.AddString(userID)
+//   fmt.Println(visitors.Count())
+func NewHyperLogLog(precision uint8) *HyperLogLog {
+	if precision < 4 || precision > 18 {
+		panic(fmt.Errorf("invalid HyperLogLog precision: %d", precision))
+	}
+	return &HyperLogLog{registers: make([]uint8, 1<<precision), precision: precision}
+}
+
+// Add adds an element.
+func (h *HyperLogLog) Add(data []byte) {
+	hash, _ := sketchHash(data)
+	h.add(hash)
+}
+
+// AddString adds a string element.
+func (h *HyperLogLog) AddString(s string) {
+	hash, _ := sketchHashString(s)
+	h.add(hash)
+}
+
+func (h *HyperLogLog) add(hash uint64) {
+	i := hash >> (64 - h.precision)
+	// rank of the first set bit in the remaining bits, the register index bits being replaced by ones
+	rank := uint8(bits.LeadingZeros64(hash<<h.precision|1<<(h.precision-1))) + 1
+	if rank > h.registers[i] {
+		h.registers[i] = rank
+	}
+}
+
+// Count returns the estimated number of distinct elements added.
+func (h *HyperLogLog) Count() uint64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	estimate := hllAlpha(len(h.registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// linear counting is more accurate for small cardinalities
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+func hllAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// Merge adds the elements of other. Both must have the same precision.
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.precision != other.precision {
+		return ErrIncompatibleSketch
+	}
+	for i, r := range other.registers {
+		h.registers[i] = max(h.registers[i], r)
+	}
+	return nil
+}
+
+// Clear removes all the elements.
+func (h *HyperLogLog) Clear() {
+	clear(h.registers)
+}
+
+// MarshalBinary encodes the HyperLogLog into a portable binary form.
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 3+len(h.registers))
+	data = append(data, hyperLogLogMagic, sketchVersion, h.precision)
+	return append(data, h.registers...), nil
+}
+
+// UnmarshalBinary replaces the HyperLogLog with data produced by MarshalBinary.
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	r := sketchReader{data: data}
+	r.header(hyperLogLogMagic)
+	if r.err != nil || len(r.data) < 1 {
+		return errCorruptedSketch("HyperLogLog")
+	}
+	precision := r.data[0]
+	if precision < 4 || precision > 18 || len(r.data)-1 != 1<<precision {
+		return errCorruptedSketch("HyperLogLog")
+	}
+	*h = HyperLogLog{registers: append([]uint8(nil), r.data[1:]...), precision: precision}
+	return nil
+}
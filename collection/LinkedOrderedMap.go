@@ -4,65 +4,75 @@ import (
 	"github.com/cnfree/common/cmp"
 	"bytes"
 	"fmt"
+	"iter"
 	"github.com/cnfree/common/utils"
 )
 
 // LinkedOrderedMap is an linked ordered map which supports iteration in insertion order.
 // It's also optimized for ordered traverse.
-type LinkedOrderedMap struct {
-	root        *lrbtNode // root of the rbtree
-	head        *lrbtNode // head and tail forms an double linked list in insertion order
-	tail        *lrbtNode
-	orderedHead *lrbtNode // orderedHead and orderedTail forms an double linked list in ascend order
-	orderedTail *lrbtNode
-	size        int            // size of the map
-	comp        cmp.Comparator // for comparing keys of the map
-}
-
-// New is the only way to get a new, ready-to-use LinkedOrderedMap object.
+type LinkedOrderedMap[K, V any] struct {
+	root        *lrbtNode[K, V] // root of the rbtree
+	head        *lrbtNode[K, V] // head and tail forms an double linked list in insertion order
+	tail        *lrbtNode[K, V]
+	orderedHead *lrbtNode[K, V] // orderedHead and orderedTail forms an double linked list in ascend order
+	orderedTail *lrbtNode[K, V]
+	size        int                // size of the map
+	comp        func(a, b K) int   // for comparing keys of the map
+	order       SerializationOrder // order of the entries when the map is serialized
+}
+
+// NewLinkedOrderedMap is the only way to get a new, ready-to-use LinkedOrderedMap object with a custom comparator.
 //
 //   comparator: for comparing keys of the LinkedOrderedMap
 //
 // Example:
 //
-//	 lom := New(func(a, b interface{}) int {return a.(int) - b.(int)})
-func NewLinkedOrderedMap(comparator cmp.Comparator) *LinkedOrderedMap {
-	return &LinkedOrderedMap{comp: comparator}
+//	 lom := NewLinkedOrderedMap[int, string](func(a, b int) int {return a - b})
+//	 lom := NewLinkedOrderedMap[interface{}, interface{}](cmp.Compare)
+func NewLinkedOrderedMap[K, V any](comparator func(a, b K) int) *LinkedOrderedMap[K, V] {
+	return &LinkedOrderedMap[K, V]{comp: comparator}
+}
+
+// NewDefaultLinkedOrderedMap returns a new, ready-to-use LinkedOrderedMap whose keys are compared with
+// their natural order.
+//
+// Example:
+//
+//	 lom := NewDefaultLinkedOrderedMap[string, int]()
+func NewDefaultLinkedOrderedMap[K cmp.Ordered, V any]() *LinkedOrderedMap[K, V] {
+	return &LinkedOrderedMap[K, V]{comp: cmp.CompareOrdered[K]}
 }
 
 // Insert inserts a new element into the LinkedOrderedMap if it doesn't already contain an element with an equivalent key.
 // Nothing will be changed if the LinkedOrderedMap already contains an element with an equivalent key.
-// Key should adhere to the comparator's type assertion, otherwise it will panic.
 //   key: key of the value to be inserted
 //   value: value to be inserted
 // Return value: true if the insertion took place and false otherwise.
-func (m *LinkedOrderedMap) Insert(key, value interface{}) bool {
+func (m *LinkedOrderedMap[K, V]) Insert(key K, value V) bool {
 	return m.set(key, value, false)
 }
 
 // Set inserts a new element into the LinkedOrderedMap or updates the existing element with the new value.
-// Key should adhere to the comparator's type assertion, otherwise it will panic.
 //   key: key of the value to be inserted/updated
 //   value: value to be inserted/updated
 // Return value: true if the insertion took place and false if the update took place.
-func (m *LinkedOrderedMap) Put(key, value interface{}) bool {
+func (m *LinkedOrderedMap[K, V]) Put(key K, value V) bool {
 	return m.set(key, value, true)
 }
 
 // Get returns value of the key and true if the given key is found.
-// If the given key is not found, it returns nil, false
-// Key should adhere to the comparator's type assertion, otherwise it will panic.
-func (m *LinkedOrderedMap) Get(key interface{}) ( /*value*/ interface{} /*found*/ , bool) {
+// If the given key is not found, it returns the zero value, false
+func (m *LinkedOrderedMap[K, V]) Get(key K) ( /*value*/ V /*found*/, bool) {
 	node := m.search(key)
 	if node != nil {
 		return node.v, true
 	}
-	return nil, false
+	var zero V
+	return zero, false
 }
 
 // Erase removes the element with the given key from the map.
-// Key should adhere to the comparator's type assertion, otherwise it will panic.
-func (m *LinkedOrderedMap) Erase(key interface{}) {
+func (m *LinkedOrderedMap[K, V]) Erase(key K) {
 	node := m.search(key)
 	if node == nil {
 		return
@@ -141,7 +151,7 @@ func (m *LinkedOrderedMap) Erase(key interface{}) {
 	}
 
 	// At this point, it's certain that node has at most one children
-	var child *lrbtNode
+	var child *lrbtNode[K, V]
 	if node.right == nil {
 		child = node.left
 	} else {
@@ -187,39 +197,100 @@ func (m *LinkedOrderedMap) Erase(key interface{}) {
 }
 
 // Empty returns true if the map does not contain any element, otherwise it returns false.
-func (m *LinkedOrderedMap) Empty() bool {
+func (m *LinkedOrderedMap[K, V]) Empty() bool {
 	return m.size == 0
 }
 
 // Size returns the number of elements in the map.
-func (m *LinkedOrderedMap) Size() int {
+func (m *LinkedOrderedMap[K, V]) Size() int {
 	return m.size
 }
 
 // Iterator returns an iterator for iterating the LinkedOrderedMap.
-func (m *LinkedOrderedMap) Iterator() MapIterator {
-	return &iterator{m.orderedHead}
+func (m *LinkedOrderedMap[K, V]) Iterator() MapIterator {
+	return &iterator[K, V]{m.orderedHead}
 }
 
 // ReverseIterator returns an iterator for iterating the LinkedOrderedMap in reverse order.
-func (m *LinkedOrderedMap) ReverseIterator() MapIterator {
-	return &reverseIterator{m.orderedTail}
+func (m *LinkedOrderedMap[K, V]) ReverseIterator() MapIterator {
+	return &reverseIterator[K, V]{m.orderedTail}
 }
 
 // LinkedIterator returns an iterator for iterating the LinkedOrderedMap in insertion order.
-func (m *LinkedOrderedMap) LinkedIterator() MapIterator {
-	return &linkedIterator{m.head}
+func (m *LinkedOrderedMap[K, V]) LinkedIterator() MapIterator {
+	return &linkedIterator[K, V]{m.head}
 }
 
 // ReverseLinkedIterator returns an iterator for iterating the LinkedOrderedMap in reverse insertion order.
-func (m *LinkedOrderedMap) ReverseLinkedIterator() MapIterator {
-	return &reverseLinkedIterator{m.tail}
+func (m *LinkedOrderedMap[K, V]) ReverseLinkedIterator() MapIterator {
+	return &reverseLinkedIterator[K, V]{m.tail}
+}
+
+// All returns an iterator over the key-value pairs of the map in ascending key order.
+//
+// Example:
+//
+//	 for k, v := range lom.All() {
+//	     fmt.Println(k, v)
+//	 }
+func (m *LinkedOrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := m.orderedHead; node != nil; node = node.orderedNext {
+			if !yield(node.k, node.v) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the key-value pairs of the map in descending key order.
+func (m *LinkedOrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := m.orderedTail; node != nil; node = node.orderedPrev {
+			if !yield(node.k, node.v) {
+				return
+			}
+		}
+	}
+}
+
+// InsertionOrder returns an iterator over the key-value pairs of the map in insertion order.
+func (m *LinkedOrderedMap[K, V]) InsertionOrder() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := m.head; node != nil; node = node.next {
+			if !yield(node.k, node.v) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the keys of the map in ascending order.
+func (m *LinkedOrderedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for node := m.orderedHead; node != nil; node = node.orderedNext {
+			if !yield(node.k) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values of the map in ascending key order.
+func (m *LinkedOrderedMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for node := m.orderedHead; node != nil; node = node.orderedNext {
+			if !yield(node.v) {
+				return
+			}
+		}
+	}
 }
 
 // TODO how to support Clone()?
 
 // Clear removes all elements from the map.
-func (m *LinkedOrderedMap) Clear() {
+func (m *LinkedOrderedMap[K, V]) Clear() {
 	m.root = nil
 	m.head = nil
 	m.tail = nil
@@ -231,33 +302,48 @@ func (m *LinkedOrderedMap) Clear() {
 // Count returns the number of elements with key key, which is either 1 or 0 since this container does not allow duplicates.
 //
 //   key: key value of the elements to count
-func (m *LinkedOrderedMap) Count(key interface{}) int {
+func (m *LinkedOrderedMap[K, V]) Count(key K) int {
 	if m.search(key) != nil {
 		return 1
 	}
 	return 0
 }
 
-func (m *LinkedOrderedMap) String() string {
+func (m *LinkedOrderedMap[K, V]) String() string {
 	var buf bytes.Buffer
 	buf.WriteString("map{ ")
 	var iter = m.LinkedIterator()
-	for {
+	for iter.IsValid() {
 		buf.WriteString(fmt.Sprintf("(key=%s, value=%s)", utils.ToString(iter.Key()), utils.ToString(iter.Value())))
 		iter.Next()
 		if iter.IsValid() {
 			buf.WriteString(", ")
-		} else {
-			break;
 		}
 	}
 	buf.WriteString(" }")
 	return buf.String()
 }
 
+// moveToBack moves node to the end of the insertion ordered linked list, as if it was the last one inserted.
+func (m *LinkedOrderedMap[K, V]) moveToBack(node *lrbtNode[K, V]) {
+	if node == m.tail {
+		return
+	}
+	if node.prev != nil {
+		node.prev.next = node.next
+	} else {
+		m.head = node.next
+	}
+	node.next.prev = node.prev
+	node.prev = m.tail
+	node.next = nil
+	m.tail.next = node
+	m.tail = node
+}
+
 // set inserts a new node into the LinkedOrderedMap or updates the existing node with the new value.
-func (m *LinkedOrderedMap) set(key, value interface{}, updateIfExist bool) bool {
-	newNode := &lrbtNode{k: key, v: value}
+func (m *LinkedOrderedMap[K, V]) set(key K, value V, updateIfExist bool) bool {
+	newNode := &lrbtNode[K, V]{k: key, v: value}
 	if m.root != nil {
 		node := m.root
 		for {
@@ -294,7 +380,7 @@ func (m *LinkedOrderedMap) set(key, value interface{}, updateIfExist bool) bool
 		m.tail = newNode
 		// ordered linked list
 		if newNode.isLeftChild() {
-			var nextNode *lrbtNode
+			var nextNode *lrbtNode[K, V]
 			if newNode.right == nil {
 				nextNode = newNode.parent
 			} else {
@@ -309,7 +395,7 @@ func (m *LinkedOrderedMap) set(key, value interface{}, updateIfExist bool) bool
 				m.orderedHead = newNode
 			}
 		} else if newNode.isRightChild() {
-			var prevNode *lrbtNode
+			var prevNode *lrbtNode[K, V]
 			if newNode.left == nil {
 				prevNode = newNode.parent
 			} else {
@@ -344,7 +430,7 @@ func (m *LinkedOrderedMap) set(key, value interface{}, updateIfExist bool) bool
 }
 
 // Case 1: root node
-func (m *LinkedOrderedMap) insertCase1(node *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) insertCase1(node *lrbtNode[K, V]) {
 	if node.parent != nil {
 		m.insertCase2(node)
 	} else { // Root node
@@ -353,14 +439,14 @@ func (m *LinkedOrderedMap) insertCase1(node *lrbtNode) {
 }
 
 // Case 2: black node can have children of any color
-func (m *LinkedOrderedMap) insertCase2(node *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) insertCase2(node *lrbtNode[K, V]) {
 	if !node.parent.isBlack {
 		m.insertCase3(node)
 	}
 }
 
 // Case 3: red nodes' children must be black
-func (m *LinkedOrderedMap) insertCase3(node *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) insertCase3(node *lrbtNode[K, V]) {
 	uncle := node.parent.sibling()
 	if !uncle.isBlackNode() {
 		node.parent.isBlack = true
@@ -373,7 +459,7 @@ func (m *LinkedOrderedMap) insertCase3(node *lrbtNode) {
 }
 
 // Case 4
-func (m *LinkedOrderedMap) insertCase4(node *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) insertCase4(node *lrbtNode[K, V]) {
 	if node.isRightChild() && node.parent.isLeftChild() {
 		m.rotateLeft(node.parent)
 		node = node.left
@@ -385,7 +471,7 @@ func (m *LinkedOrderedMap) insertCase4(node *lrbtNode) {
 }
 
 // Case 5
-func (m *LinkedOrderedMap) insertCase5(node *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) insertCase5(node *lrbtNode[K, V]) {
 	node.parent.isBlack = true
 	grandparent := node.parent.parent
 	grandparent.isBlack = false
@@ -397,14 +483,14 @@ func (m *LinkedOrderedMap) insertCase5(node *lrbtNode) {
 }
 
 // Case 1: root node
-func (m *LinkedOrderedMap) deleteCase1(node *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) deleteCase1(node *lrbtNode[K, V]) {
 	if node.parent != nil {
 		m.deleteCase2(node)
 	}
 }
 
 // Case 2: sibling node is red
-func (m *LinkedOrderedMap) deleteCase2(node *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) deleteCase2(node *lrbtNode[K, V]) {
 	sibling := node.sibling()
 	if !sibling.isBlackNode() {
 		node.parent.isBlack = false
@@ -419,7 +505,7 @@ func (m *LinkedOrderedMap) deleteCase2(node *lrbtNode) {
 }
 
 // Case 3: parent, sibling and its children are black
-func (m *LinkedOrderedMap) deleteCase3(node *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) deleteCase3(node *lrbtNode[K, V]) {
 	sibling := node.sibling()
 	if node.parent.isBlack && sibling.isBlack && sibling.left.isBlackNode() && sibling.right.isBlackNode() {
 		sibling.isBlack = false
@@ -430,7 +516,7 @@ func (m *LinkedOrderedMap) deleteCase3(node *lrbtNode) {
 }
 
 // Case 4: parent is red and sibling and its children are black
-func (m *LinkedOrderedMap) deleteCase4(node, sibling *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) deleteCase4(node, sibling *lrbtNode[K, V]) {
 	if !node.parent.isBlack && sibling.isBlack && sibling.left.isBlackNode() && sibling.right.isBlackNode() {
 		sibling.isBlack = false
 		node.parent.isBlack = true
@@ -440,7 +526,7 @@ func (m *LinkedOrderedMap) deleteCase4(node, sibling *lrbtNode) {
 }
 
 // Case 5: only one child of sibling is red
-func (m *LinkedOrderedMap) deleteCase5(node, sibling *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) deleteCase5(node, sibling *lrbtNode[K, V]) {
 	if node.isLeftChild() && sibling.isBlack && !sibling.left.isBlackNode() && sibling.right.isBlackNode() {
 		sibling.isBlack = false
 		sibling.left.isBlack = true
@@ -454,7 +540,7 @@ func (m *LinkedOrderedMap) deleteCase5(node, sibling *lrbtNode) {
 }
 
 // Case 6
-func (m *LinkedOrderedMap) deleteCase6(node *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) deleteCase6(node *lrbtNode[K, V]) {
 	sibling := node.sibling()
 	sibling.isBlack = node.parent.isBlack
 	node.parent.isBlack = true
@@ -467,7 +553,7 @@ func (m *LinkedOrderedMap) deleteCase6(node *lrbtNode) {
 	}
 }
 
-func (m *LinkedOrderedMap) rotateLeft(node *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) rotateLeft(node *lrbtNode[K, V]) {
 	right := node.right
 	m.replaceNode(node, right)
 	node.right = right.left
@@ -480,7 +566,7 @@ func (m *LinkedOrderedMap) rotateLeft(node *lrbtNode) {
 	node.nodeType = kLRBTNodeTypeLeftChild
 }
 
-func (m *LinkedOrderedMap) rotateRight(node *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) rotateRight(node *lrbtNode[K, V]) {
 	left := node.left
 	m.replaceNode(node, left)
 	node.left = left.right
@@ -493,7 +579,7 @@ func (m *LinkedOrderedMap) rotateRight(node *lrbtNode) {
 	node.nodeType = kLRBTNodeTypeRightChild
 }
 
-func (m *LinkedOrderedMap) search(key interface{}) (node *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) search(key K) (node *lrbtNode[K, V]) {
 	node = m.root
 	for node != nil {
 		ret := m.comp(key, node.k)
@@ -508,7 +594,7 @@ func (m *LinkedOrderedMap) search(key interface{}) (node *lrbtNode) {
 	return
 }
 
-func (m *LinkedOrderedMap) replaceNode(oldNode *lrbtNode, newNode *lrbtNode) {
+func (m *LinkedOrderedMap[K, V]) replaceNode(oldNode *lrbtNode[K, V], newNode *lrbtNode[K, V]) {
 	if oldNode.parent == nil {
 		m.root = newNode
 		if newNode != nil {
@@ -533,106 +619,106 @@ func (m *LinkedOrderedMap) replaceNode(oldNode *lrbtNode, newNode *lrbtNode) {
 }
 
 // Iterator is used for iterating the LinkedOrderedMap.
-type iterator struct {
-	node *lrbtNode
+type iterator[K, V any] struct {
+	node *lrbtNode[K, V]
 }
 
 // IsValid returns true if the iterator is valid for use, false otherwise.
 // We must not call Next, Key, or Value if IsValid returns false.
-func (it *iterator) IsValid() bool {
+func (it *iterator[K, V]) IsValid() bool {
 	return it.node != nil
 }
 
 // Next advances the iterator to the next element of the map
-func (it *iterator) Next() {
+func (it *iterator[K, V]) Next() {
 	it.node = it.node.orderedNext
 }
 
 // Key returns the key of the underlying element
-func (it *iterator) Key() interface{} {
+func (it *iterator[K, V]) Key() interface{} {
 	return it.node.k
 }
 
 // Value returns the value of the underlying element
-func (it *iterator) Value() interface{} {
+func (it *iterator[K, V]) Value() interface{} {
 	return it.node.v
 }
 
 // ReverseIterator is used for iterating the LinkedOrderedMap in reverse order.
-type reverseIterator struct {
-	node *lrbtNode
+type reverseIterator[K, V any] struct {
+	node *lrbtNode[K, V]
 }
 
 // IsValid returns true if the iterator is valid for use, false otherwise.
 // We must not call Next, Key, or Value if IsValid returns false.
-func (it *reverseIterator) IsValid() bool {
+func (it *reverseIterator[K, V]) IsValid() bool {
 	return it.node != nil
 }
 
 // Next advances the iterator to the next element of the map in reverse order
-func (it *reverseIterator) Next() {
+func (it *reverseIterator[K, V]) Next() {
 	it.node = it.node.orderedPrev
 }
 
 // Key returns the key of the underlying element
-func (it *reverseIterator) Key() interface{} {
+func (it *reverseIterator[K, V]) Key() interface{} {
 	return it.node.k
 }
 
 // Value returns the value of the underlying element
-func (it *reverseIterator) Value() interface{} {
+func (it *reverseIterator[K, V]) Value() interface{} {
 	return it.node.v
 }
 
 // LinkedIterator is used for iterating the LinkedOrderedMap in insertion order.
-type linkedIterator struct {
-	node *lrbtNode
+type linkedIterator[K, V any] struct {
+	node *lrbtNode[K, V]
 }
 
 // IsValid returns true if the iterator is valid for use, false otherwise.
 // We must not call Next, Key, or Value if IsValid returns false.
-func (it *linkedIterator) IsValid() bool {
+func (it *linkedIterator[K, V]) IsValid() bool {
 	return it.node != nil
 }
 
 // Next advances the iterator to the next element of the map in insertion order
-func (it *linkedIterator) Next() {
+func (it *linkedIterator[K, V]) Next() {
 	it.node = it.node.next
 }
 
 // Key returns the key of the underlying element
-func (it *linkedIterator) Key() interface{} {
+func (it *linkedIterator[K, V]) Key() interface{} {
 	return it.node.k
 }
 
 // Value returns the value of the underlying element
-func (it *linkedIterator) Value() interface{} {
+func (it *linkedIterator[K, V]) Value() interface{} {
 	return it.node.v
 }
 
 // ReverseLinkedIterator is used for iterating the LinkedOrderedMap in reverse insertion order.
-type reverseLinkedIterator struct {
-	node *lrbtNode
+type reverseLinkedIterator[K, V any] struct {
+	node *lrbtNode[K, V]
 }
 
 // IsValid returns true if the iterator is valid for use, false otherwise.
 // We must not call Next, Key, or Value if IsValid returns false.
-func (it *reverseLinkedIterator) IsValid() bool {
+func (it *reverseLinkedIterator[K, V]) IsValid() bool {
 	return it.node != nil
 }
 
 // Next advances the iterator to the next element of the map in reverse insertion order
-func (it *reverseLinkedIterator) Next() {
+func (it *reverseLinkedIterator[K, V]) Next() {
 	it.node = it.node.prev
 }
 
 // Key returns the key of the underlying element
-func (it *reverseLinkedIterator) Key() interface{} {
+func (it *reverseLinkedIterator[K, V]) Key() interface{} {
 	return it.node.k
 }
 
 // Value returns the value of the underlying element
-func (it *reverseLinkedIterator) Value() interface{} {
+func (it *reverseLinkedIterator[K, V]) Value() interface{} {
 	return it.node.v
 }
 
@@ -644,21 +730,21 @@ const (
 	kLRBTNodeTypeRightChild
 )
 
-type lrbtNode struct {
-	k           interface{}
-	v           interface{}
+type lrbtNode[K, V any] struct {
+	k           K
+	v           V
 	isBlack     bool
 	nodeType    lrbtNodeType
-	left        *lrbtNode
-	right       *lrbtNode
-	parent      *lrbtNode
-	prev        *lrbtNode
-	next        *lrbtNode
-	orderedPrev *lrbtNode
-	orderedNext *lrbtNode
+	left        *lrbtNode[K, V]
+	right       *lrbtNode[K, V]
+	parent      *lrbtNode[K, V]
+	prev        *lrbtNode[K, V]
+	next        *lrbtNode[K, V]
+	orderedPrev *lrbtNode[K, V]
+	orderedNext *lrbtNode[K, V]
 }
 
-func (node *lrbtNode) sibling() *lrbtNode {
+func (node *lrbtNode[K, V]) sibling() *lrbtNode[K, V] {
 	if node.parent != nil {
 		if node.isLeftChild() {
 			return node.parent.right
@@ -668,31 +754,31 @@ func (node *lrbtNode) sibling() *lrbtNode {
 	return nil
 }
 
-func (node *lrbtNode) rightmostChild() *lrbtNode {
+func (node *lrbtNode[K, V]) rightmostChild() *lrbtNode[K, V] {
 	for node.right != nil {
 		node = node.right
 	}
 	return node
 }
 
-func (node *lrbtNode) leftmostChild() *lrbtNode {
+func (node *lrbtNode[K, V]) leftmostChild() *lrbtNode[K, V] {
 	for node.left != nil {
 		node = node.left
 	}
 	return node
 }
 
-func (node *lrbtNode) isBlackNode() bool {
+func (node *lrbtNode[K, V]) isBlackNode() bool {
 	if node != nil {
 		return node.isBlack
 	}
 	return true
 }
 
-func (node *lrbtNode) isLeftChild() bool {
+func (node *lrbtNode[K, V]) isLeftChild() bool {
 	return node.nodeType == kLRBTNodeTypeLeftChild
 }
 
-func (node *lrbtNode) isRightChild() bool {
+func (node *lrbtNode[K, V]) isRightChild() bool {
 	return node.nodeType == kLRBTNodeTypeRightChild
 }
@@ -0,0 +1,261 @@
+package collection
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/cnfree/common/cmp"
+)
+
+// SerializationOrder is the order of the entries of a serialized LinkedOrderedMap.
+type SerializationOrder int
+
+const (
+	// SerializeInInsertionOrder serializes the entries in insertion order. It's the default.
+	SerializeInInsertionOrder SerializationOrder = iota
+	// SerializeInSortedOrder serializes the entries in ascending key order.
+	SerializeInSortedOrder
+)
+
+var (
+	_ json.Marshaler             = (*LinkedOrderedMap[string, int])(nil)
+	_ json.Unmarshaler           = (*LinkedOrderedMap[string, int])(nil)
+	_ encoding.BinaryMarshaler   = (*LinkedOrderedMap[string, int])(nil)
+	_ encoding.BinaryUnmarshaler = (*LinkedOrderedMap[string, int])(nil)
+)
+
+// SetSerializationOrder sets the order of the entries produced by MarshalJSON and MarshalBinary.
+// Unmarshaling always restores the insertion order as it's found in the data.
+func (m *LinkedOrderedMap[K, V]) SetSerializationOrder(order SerializationOrder) {
+	m.order = order
+}
+
+// MarshalJSON encodes the map as a JSON object whose members are in insertion order, or in ascending key
+// order if SerializeInSortedOrder is set.
+// Keys are encoded like encoding/json does for Go maps: strings, integers, floats and
+// encoding.TextMarshaler implementations are supported.
+func (m *LinkedOrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	var err error
+	m.serializationOrder()(func(k K, v V) bool {
+		var key string
+		if key, err = encodeJSONKey(k); err != nil {
+			return false
+		}
+		var keyData, valueData []byte
+		if keyData, err = json.Marshal(key); err != nil {
+			return false
+		}
+		if valueData, err = json.Marshal(v); err != nil {
+			return false
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		buf.Write(keyData)
+		buf.WriteByte(':')
+		buf.Write(valueData)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON replaces the content of the map with the members of a JSON object, inserted in the order
+// they appear. A JSON null leaves the map unchanged.
+// If the map has no comparator yet, such as a zero LinkedOrderedMap declared as a struct field, the natural
+// order of the key type is used.
+func (m *LinkedOrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("collection: cannot unmarshal %v into a LinkedOrderedMap", tok)
+	}
+	if err := m.ensureComparator(); err != nil {
+		return err
+	}
+
+	m.Clear()
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var k K
+		if err := decodeJSONKey(tok.(string), &k); err != nil {
+			return err
+		}
+		var v V
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		m.Put(k, v)
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// mapGob is the gob representation of a LinkedOrderedMap.
+type mapGob[K, V any] struct {
+	Keys   []K
+	Values []V
+}
+
+// MarshalBinary encodes the map with encoding/gob, in insertion order, or in ascending key order if
+// SerializeInSortedOrder is set. It makes LinkedOrderedMap usable with gob too.
+// As usual with gob, the concrete types stored in interface keys or values must be registered with gob.Register.
+func (m *LinkedOrderedMap[K, V]) MarshalBinary() ([]byte, error) {
+	g := mapGob[K, V]{Keys: make([]K, 0, m.size), Values: make([]V, 0, m.size)}
+	m.serializationOrder()(func(k K, v V) bool {
+		g.Keys = append(g.Keys, k)
+		g.Values = append(g.Values, v)
+		return true
+	})
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(g); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the content of the map with data produced by MarshalBinary.
+func (m *LinkedOrderedMap[K, V]) UnmarshalBinary(data []byte) error {
+	var g mapGob[K, V]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&g); err != nil {
+		return err
+	}
+	if len(g.Keys) != len(g.Values) {
+		return fmt.Errorf("collection: corrupted LinkedOrderedMap data, %d keys for %d values", len(g.Keys), len(g.Values))
+	}
+	if err := m.ensureComparator(); err != nil {
+		return err
+	}
+	m.Clear()
+	for i := range g.Keys {
+		m.Put(g.Keys[i], g.Values[i])
+	}
+	return nil
+}
+
+func (m *LinkedOrderedMap[K, V]) serializationOrder() func(yield func(K, V) bool) {
+	if m.order == SerializeInSortedOrder {
+		return m.All()
+	}
+	return m.InsertionOrder()
+}
+
+func (m *LinkedOrderedMap[K, V]) ensureComparator() error {
+	if m.comp != nil {
+		return nil
+	}
+	comp := defaultComparator[K]()
+	if comp == nil {
+		var k K
+		return fmt.Errorf("collection: no comparator for the key type %T of a LinkedOrderedMap", k)
+	}
+	m.comp = comp
+	return nil
+}
+
+// defaultComparator returns a comparator for the natural order of K, or nil if K has none.
+func defaultComparator[K any]() func(a, b K) int {
+	t := reflect.TypeOf((*K)(nil)).Elem()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b K) int {
+			return cmp.CompareOrdered(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b K) int {
+			return cmp.CompareOrdered(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(a, b K) int {
+			return cmp.CompareOrdered(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		}
+	case reflect.String:
+		return func(a, b K) int {
+			return cmp.CompareOrdered(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		}
+	case reflect.Interface:
+		return func(a, b K) int {
+			return cmp.Compare(a, b)
+		}
+	}
+	return nil
+}
+
+func encodeJSONKey(k interface{}) (string, error) {
+	if tm, ok := k.(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	v := reflect.ValueOf(k)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("collection: unsupported JSON key type %T", k)
+}
+
+func decodeJSONKey(s string, k interface{}) error {
+	if tu, ok := k.(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(s))
+	}
+	v := reflect.ValueOf(k).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return nil
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(s))
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+		return nil
+	}
+	return fmt.Errorf("collection: unsupported JSON key type %s", v.Type())
+}
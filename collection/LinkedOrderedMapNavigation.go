@@ -0,0 +1,394 @@
+package collection
+
+import "iter"
+
+// First returns the smallest key of the map and its value, or false if the map is empty.
+func (m *LinkedOrderedMap[K, V]) First() (K, V, bool) {
+	return m.entry(m.orderedHead)
+}
+
+// Last returns the largest key of the map and its value, or false if the map is empty.
+func (m *LinkedOrderedMap[K, V]) Last() (K, V, bool) {
+	return m.entry(m.orderedTail)
+}
+
+// PollFirst removes the smallest key of the map and returns it with its value, or false if the map is empty.
+func (m *LinkedOrderedMap[K, V]) PollFirst() (K, V, bool) {
+	k, v, ok := m.entry(m.orderedHead)
+	if ok {
+		m.Erase(k)
+	}
+	return k, v, ok
+}
+
+// PollLast removes the largest key of the map and returns it with its value, or false if the map is empty.
+func (m *LinkedOrderedMap[K, V]) PollLast() (K, V, bool) {
+	k, v, ok := m.entry(m.orderedTail)
+	if ok {
+		m.Erase(k)
+	}
+	return k, v, ok
+}
+
+// Floor returns the greatest key less than or equal to the given key and its value,
+// or false if there is no such key.
+func (m *LinkedOrderedMap[K, V]) Floor(key K) (K, V, bool) {
+	return m.entry(m.floorNode(key, true))
+}
+
+// Ceiling returns the least key greater than or equal to the given key and its value,
+// or false if there is no such key.
+func (m *LinkedOrderedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	return m.entry(m.ceilingNode(key, true))
+}
+
+// Lower returns the greatest key strictly less than the given key and its value,
+// or false if there is no such key.
+func (m *LinkedOrderedMap[K, V]) Lower(key K) (K, V, bool) {
+	return m.entry(m.floorNode(key, false))
+}
+
+// Higher returns the least key strictly greater than the given key and its value,
+// or false if there is no such key.
+func (m *LinkedOrderedMap[K, V]) Higher(key K) (K, V, bool) {
+	return m.entry(m.ceilingNode(key, false))
+}
+
+// IteratorFrom returns an iterator for iterating the LinkedOrderedMap in ascending order,
+// starting at the least key greater than or equal to the given key.
+func (m *LinkedOrderedMap[K, V]) IteratorFrom(key K) MapIterator {
+	return &iterator[K, V]{m.ceilingNode(key, true)}
+}
+
+// ReverseIteratorFrom returns an iterator for iterating the LinkedOrderedMap in descending order,
+// starting at the greatest key less than or equal to the given key.
+func (m *LinkedOrderedMap[K, V]) ReverseIteratorFrom(key K) MapIterator {
+	return &reverseIterator[K, V]{m.floorNode(key, true)}
+}
+
+// AllFrom returns an iterator over the key-value pairs of the map in ascending key order,
+// starting at the least key greater than or equal to the given key.
+func (m *LinkedOrderedMap[K, V]) AllFrom(key K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := m.ceilingNode(key, true); node != nil; node = node.orderedNext {
+			if !yield(node.k, node.v) {
+				return
+			}
+		}
+	}
+}
+
+// BackwardFrom returns an iterator over the key-value pairs of the map in descending key order,
+// starting at the greatest key less than or equal to the given key.
+func (m *LinkedOrderedMap[K, V]) BackwardFrom(key K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := m.floorNode(key, true); node != nil; node = node.orderedPrev {
+			if !yield(node.k, node.v) {
+				return
+			}
+		}
+	}
+}
+
+// SubMap returns a view of the portion of the map whose keys range from fromKey to toKey.
+// The view is backed by the map, so changes in the map are reflected in the view.
+//
+//   fromInclusive: true if fromKey is part of the view
+//   toInclusive: true if toKey is part of the view
+//
+// Example:
+//
+//   // all the entries with 10 <= key < 20
+//   for k, v := range lom.SubMap(10, true, 20, false).All() {
+//       fmt.Println(k, v)
+//   }
+func (m *LinkedOrderedMap[K, V]) SubMap(fromKey K, fromInclusive bool, toKey K, toInclusive bool) *RangeView[K, V] {
+	return &RangeView[K, V]{
+		m:      m,
+		lo:     fromKey,
+		hasLo:  true,
+		loIncl: fromInclusive,
+		hi:     toKey,
+		hasHi:  true,
+		hiIncl: toInclusive,
+	}
+}
+
+// HeadMap returns a view of the portion of the map whose keys are less than (or equal to, if inclusive is
+// true) toKey. The view is backed by the map.
+func (m *LinkedOrderedMap[K, V]) HeadMap(toKey K, inclusive bool) *RangeView[K, V] {
+	return &RangeView[K, V]{m: m, hi: toKey, hasHi: true, hiIncl: inclusive}
+}
+
+// TailMap returns a view of the portion of the map whose keys are greater than (or equal to, if inclusive
+// is true) fromKey. The view is backed by the map.
+func (m *LinkedOrderedMap[K, V]) TailMap(fromKey K, inclusive bool) *RangeView[K, V] {
+	return &RangeView[K, V]{m: m, lo: fromKey, hasLo: true, loIncl: inclusive}
+}
+
+// entry returns the key and the value of node, or false if node is nil.
+func (m *LinkedOrderedMap[K, V]) entry(node *lrbtNode[K, V]) (K, V, bool) {
+	if node == nil {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return node.k, node.v, true
+}
+
+// floorNode returns the node with the greatest key less than (or equal to, if inclusive is true) key.
+func (m *LinkedOrderedMap[K, V]) floorNode(key K, inclusive bool) *lrbtNode[K, V] {
+	var best *lrbtNode[K, V]
+	node := m.root
+	for node != nil {
+		ret := m.comp(key, node.k)
+		if ret == 0 && inclusive {
+			return node
+		}
+		if ret > 0 {
+			best = node
+			node = node.right
+		} else {
+			node = node.left
+		}
+	}
+	return best
+}
+
+// ceilingNode returns the node with the least key greater than (or equal to, if inclusive is true) key.
+func (m *LinkedOrderedMap[K, V]) ceilingNode(key K, inclusive bool) *lrbtNode[K, V] {
+	var best *lrbtNode[K, V]
+	node := m.root
+	for node != nil {
+		ret := m.comp(key, node.k)
+		if ret == 0 && inclusive {
+			return node
+		}
+		if ret < 0 {
+			best = node
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+	return best
+}
+
+// RangeView is a view of the entries of a LinkedOrderedMap whose keys are within a range.
+// It's backed by the map, so changes in the map are reflected in the view.
+// All the operations are performed on the map, in O(log n), except Size which is O(n).
+type RangeView[K, V any] struct {
+	m      *LinkedOrderedMap[K, V]
+	lo     K
+	hasLo  bool
+	loIncl bool
+	hi     K
+	hasHi  bool
+	hiIncl bool
+}
+
+// Get returns value of the key and true if the given key is found within the range of the view.
+func (r *RangeView[K, V]) Get(key K) (V, bool) {
+	if !r.inRange(key) {
+		var zero V
+		return zero, false
+	}
+	return r.m.Get(key)
+}
+
+// Put inserts or updates an element in the map.
+// Return value: false if the key is out of the range of the view, in which case nothing is changed.
+func (r *RangeView[K, V]) Put(key K, value V) bool {
+	if !r.inRange(key) {
+		return false
+	}
+	r.m.Put(key, value)
+	return true
+}
+
+// Erase removes the element with the given key from the map, if it's within the range of the view.
+func (r *RangeView[K, V]) Erase(key K) {
+	if r.inRange(key) {
+		r.m.Erase(key)
+	}
+}
+
+// Clear removes all the elements of the view from the map.
+func (r *RangeView[K, V]) Clear() {
+	for {
+		k, _, ok := r.First()
+		if !ok {
+			return
+		}
+		r.m.Erase(k)
+	}
+}
+
+// Size returns the number of elements in the view.
+func (r *RangeView[K, V]) Size() int {
+	n := 0
+	for node := r.firstNode(); node != nil && r.belowHi(node.k); node = node.orderedNext {
+		n++
+	}
+	return n
+}
+
+// Empty returns true if the view does not contain any element, otherwise it returns false.
+func (r *RangeView[K, V]) Empty() bool {
+	_, _, ok := r.First()
+	return !ok
+}
+
+// First returns the smallest key of the view and its value, or false if the view is empty.
+func (r *RangeView[K, V]) First() (K, V, bool) {
+	return r.m.entry(r.clip(r.firstNode()))
+}
+
+// Last returns the largest key of the view and its value, or false if the view is empty.
+func (r *RangeView[K, V]) Last() (K, V, bool) {
+	return r.m.entry(r.clip(r.lastNode()))
+}
+
+// Floor returns the greatest key of the view less than or equal to the given key and its value.
+func (r *RangeView[K, V]) Floor(key K) (K, V, bool) {
+	if !r.belowHi(key) {
+		return r.Last()
+	}
+	return r.m.entry(r.clip(r.m.floorNode(key, true)))
+}
+
+// Ceiling returns the least key of the view greater than or equal to the given key and its value.
+func (r *RangeView[K, V]) Ceiling(key K) (K, V, bool) {
+	if !r.aboveLo(key) {
+		return r.First()
+	}
+	return r.m.entry(r.clip(r.m.ceilingNode(key, true)))
+}
+
+// Iterator returns an iterator for iterating the view in ascending order.
+func (r *RangeView[K, V]) Iterator() MapIterator {
+	return &rangeIterator[K, V]{r, r.clip(r.firstNode())}
+}
+
+// ReverseIterator returns an iterator for iterating the view in descending order.
+func (r *RangeView[K, V]) ReverseIterator() MapIterator {
+	return &reverseRangeIterator[K, V]{r, r.clip(r.lastNode())}
+}
+
+// All returns an iterator over the key-value pairs of the view in ascending key order.
+func (r *RangeView[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := r.clip(r.firstNode()); node != nil; node = r.clip(node.orderedNext) {
+			if !yield(node.k, node.v) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the key-value pairs of the view in descending key order.
+func (r *RangeView[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := r.clip(r.lastNode()); node != nil; node = r.clip(node.orderedPrev) {
+			if !yield(node.k, node.v) {
+				return
+			}
+		}
+	}
+}
+
+func (r *RangeView[K, V]) firstNode() *lrbtNode[K, V] {
+	if r.hasLo {
+		return r.m.ceilingNode(r.lo, r.loIncl)
+	}
+	return r.m.orderedHead
+}
+
+func (r *RangeView[K, V]) lastNode() *lrbtNode[K, V] {
+	if r.hasHi {
+		return r.m.floorNode(r.hi, r.hiIncl)
+	}
+	return r.m.orderedTail
+}
+
+// clip returns node if its key is within the range of the view, nil otherwise.
+func (r *RangeView[K, V]) clip(node *lrbtNode[K, V]) *lrbtNode[K, V] {
+	if node == nil || !r.inRange(node.k) {
+		return nil
+	}
+	return node
+}
+
+func (r *RangeView[K, V]) inRange(key K) bool {
+	return r.aboveLo(key) && r.belowHi(key)
+}
+
+func (r *RangeView[K, V]) aboveLo(key K) bool {
+	if !r.hasLo {
+		return true
+	}
+	ret := r.m.comp(key, r.lo)
+	return ret > 0 || (ret == 0 && r.loIncl)
+}
+
+func (r *RangeView[K, V]) belowHi(key K) bool {
+	if !r.hasHi {
+		return true
+	}
+	ret := r.m.comp(key, r.hi)
+	return ret < 0 || (ret == 0 && r.hiIncl)
+}
+
+// rangeIterator is used for iterating a RangeView.
+type rangeIterator[K, V any] struct {
+	view *RangeView[K, V]
+	node *lrbtNode[K, V]
+}
+
+// IsValid returns true if the iterator is valid for use, false otherwise.
+// We must not call Next, Key, or Value if IsValid returns false.
+func (it *rangeIterator[K, V]) IsValid() bool {
+	return it.node != nil
+}
+
+// Next advances the iterator to the next element of the view
+func (it *rangeIterator[K, V]) Next() {
+	it.node = it.view.clip(it.node.orderedNext)
+}
+
+// Key returns the key of the underlying element
+func (it *rangeIterator[K, V]) Key() interface{} {
+	return it.node.k
+}
+
+// Value returns the value of the underlying element
+func (it *rangeIterator[K, V]) Value() interface{} {
+	return it.node.v
+}
+
+// reverseRangeIterator is used for iterating a RangeView in reverse order.
+type reverseRangeIterator[K, V any] struct {
+	view *RangeView[K, V]
+	node *lrbtNode[K, V]
+}
+
+// IsValid returns true if the iterator is valid for use, false otherwise.
+// We must not call Next, Key, or Value if IsValid returns false.
+func (it *reverseRangeIterator[K, V]) IsValid() bool {
+	return it.node != nil
+}
+
+// Next advances the iterator to the next element of the view in reverse order
+func (it *reverseRangeIterator[K, V]) Next() {
+	it.node = it.view.clip(it.node.orderedPrev)
+}
+
+// Key returns the key of the underlying element
+func (it *reverseRangeIterator[K, V]) Key() interface{} {
+	return it.node.k
+}
+
+// Value returns the value of the underlying element
+func (it *reverseRangeIterator[K, V]) Value() interface{} {
+	return it.node.v
+}
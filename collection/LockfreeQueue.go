@@ -1,6 +1,7 @@
 package collection
 
 import (
+	"context"
 	"sync/atomic"
 	"unsafe"
 )
@@ -9,63 +10,121 @@ import (
 //
 // Example:
 //
-//   lfq := queue.NewLockfreeQueue()
+//   lfq := collection.NewLockfreeQueue[int]()
 //   lfq.Push(100)
-//   v := lfq.Pop()
-func NewLockfreeQueue() *LockfreeQueue {
-	var lfq LockfreeQueue
+//   v, ok := lfq.Pop()
+func NewLockfreeQueue[T any]() *LockfreeQueue[T] {
+	var lfq LockfreeQueue[T]
 	lfq.head = unsafe.Pointer(&lfq.dummy)
 	lfq.tail = lfq.head
+	lfq.notEmpty = make(chan struct{}, 1)
 	return &lfq
 }
 
-// LockfreeQueue is a goroutine-safe Queue implementation.
+// LockfreeQueue is a goroutine-safe unbounded Queue implementation.
 // The overall performance of LockfreeQueue is much better than List+Mutex(standard package).
-type LockfreeQueue struct {
-	head  unsafe.Pointer
-	tail  unsafe.Pointer
-	dummy lfqNode
+type LockfreeQueue[T any] struct {
+	head     unsafe.Pointer
+	tail     unsafe.Pointer
+	dummy    lfqNode[T]
+	len      int64
+	notEmpty chan struct{}
 }
 
-// Pop returns (and removes) an element from the front of the queue, or nil if the queue is empty.
+// Pop returns (and removes) an element from the front of the queue and true,
+// or the zero value and false if the queue is empty.
 // It performs about 100% better than list.List.Front() and list.List.Remove() with sync.Mutex.
-func (lfq *LockfreeQueue) Pop() interface{} {
+func (lfq *LockfreeQueue[T]) Pop() (T, bool) {
 	for {
 		h := atomic.LoadPointer(&lfq.head)
-		rh := (*lfqNode)(h)
-		n := (*lfqNode)(atomic.LoadPointer(&rh.next))
+		rh := (*lfqNode[T])(h)
+		n := (*lfqNode[T])(atomic.LoadPointer(&rh.next))
 		if n != nil {
-			if atomic.CompareAndSwapPointer(&lfq.head, h, rh.next) {
-				return n.val
+			if atomic.CompareAndSwapPointer(&lfq.head, h, unsafe.Pointer(n)) {
+				val := n.val
+				// n is the new dummy node, it mustn't keep the value alive
+				var zero T
+				n.val = zero
+				atomic.AddInt64(&lfq.len, -1)
+				return val, true
 			} else {
 				continue
 			}
 		} else {
-			return nil
+			var zero T
+			return zero, false
+		}
+	}
+}
+
+// PopWait is the same as Pop, except that it waits for an element if the queue is empty.
+// It returns ctx's error if ctx is done before an element could be popped.
+func (lfq *LockfreeQueue[T]) PopWait(ctx context.Context) (T, error) {
+	for {
+		if val, ok := lfq.Pop(); ok {
+			if lfq.Len() > 0 {
+				// passes the notification on to another waiter
+				notify(lfq.notEmpty)
+			}
+			return val, nil
+		}
+		select {
+		case <-lfq.notEmpty:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
 		}
 	}
 }
 
 // Push inserts an element to the back of the queue.
 // It performs exactly the same as list.List.PushBack() with sync.Mutex.
-func (lfq *LockfreeQueue) Push(val interface{}) {
-	node := unsafe.Pointer(&lfqNode{val: val})
+func (lfq *LockfreeQueue[T]) Push(val T) {
+	node := unsafe.Pointer(&lfqNode[T]{val: val})
 	for {
 		t := atomic.LoadPointer(&lfq.tail)
-		rt := (*lfqNode)(t)
+		rt := (*lfqNode[T])(t)
 		if atomic.CompareAndSwapPointer(&rt.next, nil, node) {
 			// It'll be a dead loop if atomic.StorePointer() is used.
 			// Don't know why.
 			// atomic.StorePointer(&lfq.tail, node)
 			atomic.CompareAndSwapPointer(&lfq.tail, t, node)
+			atomic.AddInt64(&lfq.len, 1)
+			notify(lfq.notEmpty)
 			return
 		} else {
+			// helps the pusher which is about to move the tail
+			atomic.CompareAndSwapPointer(&lfq.tail, t, atomic.LoadPointer(&rt.next))
 			continue
 		}
 	}
 }
 
-type lfqNode struct {
-	val  interface{}
+// Len returns the number of elements in the queue.
+// It's only a snapshot when the queue is used concurrently.
+func (lfq *LockfreeQueue[T]) Len() int {
+	n := atomic.LoadInt64(&lfq.len)
+	if n < 0 {
+		return 0
+	}
+	return int(n)
+}
+
+// Empty returns true if the queue does not contain any element, otherwise it returns false.
+func (lfq *LockfreeQueue[T]) Empty() bool {
+	h := (*lfqNode[T])(atomic.LoadPointer(&lfq.head))
+	return atomic.LoadPointer(&h.next) == nil
+}
+
+type lfqNode[T any] struct {
+	val  T
 	next unsafe.Pointer
 }
+
+// notify wakes up one waiter of c, if any, without blocking.
+func notify(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,404 @@
+package collection
+
+import (
+	"iter"
+	"slices"
+)
+
+// keyStore is the map backing the multimaps, multisets and tables: a Go map for the hashed variants,
+// iterated in no particular order, or a LinkedOrderedMap for the sorted ones, iterated in key order.
+type keyStore[K, V any] interface {
+	get(key K) (V, bool)
+	put(key K, value V)
+	remove(key K) bool
+	len() int
+	all() iter.Seq2[K, V]
+	clear()
+}
+
+type hashStore[K comparable, V any] map[K]V
+
+func (s hashStore[K, V]) get(key K) (V, bool) {
+	v, ok := s[key]
+	return v, ok
+}
+
+func (s hashStore[K, V]) put(key K, value V) {
+	s[key] = value
+}
+
+func (s hashStore[K, V]) remove(key K) bool {
+	_, ok := s[key]
+	delete(s, key)
+	return ok
+}
+
+func (s hashStore[K, V]) len() int {
+	return len(s)
+}
+
+func (s hashStore[K, V]) all() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range s {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s hashStore[K, V]) clear() {
+	clear(s)
+}
+
+type treeStore[K, V any] struct {
+	m *LinkedOrderedMap[K, V]
+}
+
+func (s treeStore[K, V]) get(key K) (V, bool) {
+	return s.m.Get(key)
+}
+
+func (s treeStore[K, V]) put(key K, value V) {
+	s.m.Put(key, value)
+}
+
+func (s treeStore[K, V]) remove(key K) bool {
+	if s.m.Count(key) == 0 {
+		return false
+	}
+	s.m.Erase(key)
+	return true
+}
+
+func (s treeStore[K, V]) len() int {
+	return s.m.Size()
+}
+
+func (s treeStore[K, V]) all() iter.Seq2[K, V] {
+	return s.m.All()
+}
+
+func (s treeStore[K, V]) clear() {
+	s.m.Clear()
+}
+
+func newHashStore[K comparable, V any]() keyStore[K, V] {
+	return hashStore[K, V]{}
+}
+
+func treeStoreFactory[K, V any](comparator func(a, b K) int) func() keyStore[K, V] {
+	return func() keyStore[K, V] {
+		return treeStore[K, V]{NewLinkedOrderedMap[K, V](comparator)}
+	}
+}
+
+// ListMultimap maps each key to a list of values, duplicates included, kept in the order they were added.
+// A ListMultimap is not safe for concurrent use.
+type ListMultimap[K any, V comparable] struct {
+	lists keyStore[K, []V]
+	size  int
+}
+
+// NewHashListMultimap returns a new, ready-to-use ListMultimap whose keys are iterated in no particular order.
+//
+// Example:
+//
+//   tags := collection.NewHashListMultimap[string, string]()
+//   tags.Put("post-1", "go")
+//   tags.Put("post-1", "generics")
+//   tags.Get("post-1") // [go generics]
+func NewHashListMultimap[K, V comparable]() *ListMultimap[K, V] {
+	return &ListMultimap[K, V]{lists: newHashStore[K, []V]()}
+}
+
+// NewTreeListMultimap returns a new, ready-to-use ListMultimap whose keys are iterated in the order of comparator.
+//
+// Example:
+//
+//   byDay := collection.NewTreeListMultimap[string, *Event](strings.Compare)
+func NewTreeListMultimap[K any, V comparable](comparator func(a, b K) int) *ListMultimap[K, V] {
+	return &ListMultimap[K, V]{lists: treeStoreFactory[K, []V](comparator)()}
+}
+
+// Put adds a value to the list of the key.
+func (m *ListMultimap[K, V]) Put(key K, value V) {
+	m.PutAll(key, value)
+}
+
+// PutAll adds values to the list of the key.
+func (m *ListMultimap[K, V]) PutAll(key K, values ...V) {
+	if len(values) == 0 {
+		return
+	}
+	list, _ := m.lists.get(key)
+	m.lists.put(key, append(list, values...))
+	m.size += len(values)
+}
+
+// Get returns a copy of the list of values of the key, nil if there are none.
+func (m *ListMultimap[K, V]) Get(key K) []V {
+	list, _ := m.lists.get(key)
+	return slices.Clone(list)
+}
+
+// Remove removes the first occurrence of value from the list of the key and returns true, or returns
+// false if the list doesn't contain value.
+func (m *ListMultimap[K, V]) Remove(key K, value V) bool {
+	list, _ := m.lists.get(key)
+	i := slices.Index(list, value)
+	if i < 0 {
+		return false
+	}
+	m.size--
+	if len(list) == 1 {
+		m.lists.remove(key)
+	} else {
+		m.lists.put(key, slices.Delete(list, i, i+1))
+	}
+	return true
+}
+
+// RemoveAll removes the key and returns its values.
+func (m *ListMultimap[K, V]) RemoveAll(key K) []V {
+	list, _ := m.lists.get(key)
+	if m.lists.remove(key) {
+		m.size -= len(list)
+	}
+	return list
+}
+
+// ContainsKey returns true if the key has at least one value.
+func (m *ListMultimap[K, V]) ContainsKey(key K) bool {
+	_, ok := m.lists.get(key)
+	return ok
+}
+
+// ContainsEntry returns true if the list of the key contains value.
+func (m *ListMultimap[K, V]) ContainsEntry(key K, value V) bool {
+	list, _ := m.lists.get(key)
+	return slices.Contains(list, value)
+}
+
+// Len returns the number of key-value pairs.
+func (m *ListMultimap[K, V]) Len() int {
+	return m.size
+}
+
+// KeyCount returns the number of distinct keys.
+func (m *ListMultimap[K, V]) KeyCount() int {
+	return m.lists.len()
+}
+
+// Keys returns an iterator over the distinct keys.
+func (m *ListMultimap[K, V]) Keys() iter.Seq[K] {
+	return storeKeys(m.lists)
+}
+
+// Lists returns an iterator over the keys and their lists of values, which must not be modified.
+func (m *ListMultimap[K, V]) Lists() iter.Seq2[K, []V] {
+	return m.lists.all()
+}
+
+// All returns an iterator over the key-value pairs, the values of a key in the order they were added.
+func (m *ListMultimap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, list := range m.lists.all() {
+			for _, v := range list {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Iterator returns an iterator over the key-value pairs, in the order of All.
+func (m *ListMultimap[K, V]) Iterator() MapIterator {
+	return newSnapshotIterator(m.All(), m.size)
+}
+
+// Clear removes all the key-value pairs.
+func (m *ListMultimap[K, V]) Clear() {
+	m.lists.clear()
+	m.size = 0
+}
+
+// SetMultimap maps each key to a set of distinct values.
+// A SetMultimap is not safe for concurrent use.
+type SetMultimap[K, V any] struct {
+	sets   keyStore[K, keyStore[V, struct{}]]
+	newSet func() keyStore[V, struct{}]
+	size   int
+}
+
+// NewHashSetMultimap returns a new, ready-to-use SetMultimap whose keys and values are iterated in no
+// particular order.
+//
+// Example:
+//
+//   followers := collection.NewHashSetMultimap[UserID, UserID]()
+//   followers.Put(alice, bob)
+func NewHashSetMultimap[K, V comparable]() *SetMultimap[K, V] {
+	return &SetMultimap[K, V]{sets: newHashStore[K, keyStore[V, struct{}]](), newSet: newHashStore[V, struct{}]}
+}
+
+// NewTreeSetMultimap returns a new, ready-to-use SetMultimap whose keys and values are iterated in the
+// order of their comparators.
+//
+// Example:
+//
+//   index := collection.NewTreeSetMultimap[string, int](strings.Compare, cmp.CompareOrdered[int])
+func NewTreeSetMultimap[K, V any](keyComparator func(a, b K) int, valueComparator func(a, b V) int) *SetMultimap[K, V] {
+	return &SetMultimap[K, V]{
+		sets:   treeStoreFactory[K, keyStore[V, struct{}]](keyComparator)(),
+		newSet: treeStoreFactory[V, struct{}](valueComparator),
+	}
+}
+
+// Put adds a value to the set of the key and returns true, or returns false if the set already contains it.
+func (m *SetMultimap[K, V]) Put(key K, value V) bool {
+	set, ok := m.sets.get(key)
+	if !ok {
+		set = m.newSet()
+		m.sets.put(key, set)
+	}
+	if _, found := set.get(value); found {
+		return false
+	}
+	set.put(value, struct{}{})
+	m.size++
+	return true
+}
+
+// Get returns the values of the key, nil if there are none.
+func (m *SetMultimap[K, V]) Get(key K) []V {
+	set, ok := m.sets.get(key)
+	if !ok {
+		return nil
+	}
+	values := make([]V, 0, set.len())
+	for v := range set.all() {
+		values = append(values, v)
+	}
+	return values
+}
+
+// Remove removes a value from the set of the key and returns true, or returns false if the set doesn't contain it.
+func (m *SetMultimap[K, V]) Remove(key K, value V) bool {
+	set, ok := m.sets.get(key)
+	if !ok || !set.remove(value) {
+		return false
+	}
+	m.size--
+	if set.len() == 0 {
+		m.sets.remove(key)
+	}
+	return true
+}
+
+// RemoveAll removes the key and returns its values.
+func (m *SetMultimap[K, V]) RemoveAll(key K) []V {
+	values := m.Get(key)
+	if m.sets.remove(key) {
+		m.size -= len(values)
+	}
+	return values
+}
+
+// ContainsKey returns true if the key has at least one value.
+func (m *SetMultimap[K, V]) ContainsKey(key K) bool {
+	_, ok := m.sets.get(key)
+	return ok
+}
+
+// ContainsEntry returns true if the set of the key contains value.
+func (m *SetMultimap[K, V]) ContainsEntry(key K, value V) bool {
+	set, ok := m.sets.get(key)
+	if !ok {
+		return false
+	}
+	_, ok = set.get(value)
+	return ok
+}
+
+// Len returns the number of key-value pairs.
+func (m *SetMultimap[K, V]) Len() int {
+	return m.size
+}
+
+// KeyCount returns the number of distinct keys.
+func (m *SetMultimap[K, V]) KeyCount() int {
+	return m.sets.len()
+}
+
+// Keys returns an iterator over the distinct keys.
+func (m *SetMultimap[K, V]) Keys() iter.Seq[K] {
+	return storeKeys(m.sets)
+}
+
+// All returns an iterator over the key-value pairs.
+func (m *SetMultimap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, set := range m.sets.all() {
+			for v := range set.all() {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Iterator returns an iterator over the key-value pairs, in the order of All.
+func (m *SetMultimap[K, V]) Iterator() MapIterator {
+	return newSnapshotIterator(m.All(), m.size)
+}
+
+// Clear removes all the key-value pairs.
+func (m *SetMultimap[K, V]) Clear() {
+	m.sets.clear()
+	m.size = 0
+}
+
+func storeKeys[K, V any](s keyStore[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range s.all() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// snapshotIterator is a MapIterator over a copy of the entries of a collection, taken when it's created.
+type snapshotIterator[K, V any] struct {
+	keys   []K
+	values []V
+	index  int
+}
+
+func newSnapshotIterator[K, V any](seq iter.Seq2[K, V], size int) *snapshotIterator[K, V] {
+	it := &snapshotIterator[K, V]{keys: make([]K, 0, size), values: make([]V, 0, size)}
+	for k, v := range seq {
+		it.keys = append(it.keys, k)
+		it.values = append(it.values, v)
+	}
+	return it
+}
+
+func (it *snapshotIterator[K, V]) IsValid() bool {
+	return it.index < len(it.keys)
+}
+
+func (it *snapshotIterator[K, V]) Next() {
+	it.index++
+}
+
+func (it *snapshotIterator[K, V]) Key() interface{} {
+	return it.keys[it.index]
+}
+
+func (it *snapshotIterator[K, V]) Value() interface{} {
+	return it.values[it.index]
+}
@@ -0,0 +1,115 @@
+package collection
+
+import "iter"
+
+// Multiset is a set counting the occurrences of its elements, also known as a bag.
+// A Multiset is not safe for concurrent use.
+type Multiset[T any] struct {
+	counts keyStore[T, int]
+	size   int
+}
+
+// NewHashMultiset returns a new, ready-to-use Multiset whose elements are iterated in no particular order.
+//
+// Example:
+//
+//   words := collection.NewHashMultiset[string]()
+//   for _, w := range strings.Fields(text) {
+//       words.Add(w)
+//   }
+//   words.Count("the")
+func NewHashMultiset[T comparable]() *Multiset[T] {
+	return &Multiset[T]{counts: newHashStore[T, int]()}
+}
+
+// NewTreeMultiset returns a new, ready-to-use Multiset whose elements are iterated in the order of comparator.
+func NewTreeMultiset[T any](comparator func(a, b T) int) *Multiset[T] {
+	return &Multiset[T]{counts: treeStoreFactory[T, int](comparator)()}
+}
+
+// Add adds an occurrence of the element.
+func (s *Multiset[T]) Add(value T) {
+	s.AddN(value, 1)
+}
+
+// AddN adds n occurrences of the element. It does nothing if n isn't positive.
+func (s *Multiset[T]) AddN(value T, n int) {
+	if n <= 0 {
+		return
+	}
+	count, _ := s.counts.get(value)
+	s.counts.put(value, count+n)
+	s.size += n
+}
+
+// Remove removes an occurrence of the element and returns true, or returns false if there are none.
+func (s *Multiset[T]) Remove(value T) bool {
+	return s.RemoveN(value, 1) == 1
+}
+
+// RemoveN removes up to n occurrences of the element and returns the number of occurrences removed.
+func (s *Multiset[T]) RemoveN(value T, n int) int {
+	count, _ := s.counts.get(value)
+	n = min(max(n, 0), count)
+	if n == 0 {
+		return 0
+	}
+	s.SetCount(value, count-n)
+	return n
+}
+
+// SetCount sets the number of occurrences of the element, removing it if count isn't positive.
+func (s *Multiset[T]) SetCount(value T, count int) {
+	old, _ := s.counts.get(value)
+	if count <= 0 {
+		s.counts.remove(value)
+		count = 0
+	} else {
+		s.counts.put(value, count)
+	}
+	s.size += count - old
+}
+
+// Count returns the number of occurrences of the element.
+func (s *Multiset[T]) Count(value T) int {
+	count, _ := s.counts.get(value)
+	return count
+}
+
+// Contains returns true if the element occurs at least once.
+func (s *Multiset[T]) Contains(value T) bool {
+	_, ok := s.counts.get(value)
+	return ok
+}
+
+// Len returns the number of occurrences of all the elements.
+func (s *Multiset[T]) Len() int {
+	return s.size
+}
+
+// DistinctCount returns the number of distinct elements.
+func (s *Multiset[T]) DistinctCount() int {
+	return s.counts.len()
+}
+
+// Distinct returns an iterator over the distinct elements.
+func (s *Multiset[T]) Distinct() iter.Seq[T] {
+	return storeKeys(s.counts)
+}
+
+// All returns an iterator over the distinct elements and their numbers of occurrences.
+func (s *Multiset[T]) All() iter.Seq2[T, int] {
+	return s.counts.all()
+}
+
+// Iterator returns an iterator whose keys are the distinct elements and whose values are their numbers of
+// occurrences, in the order of All.
+func (s *Multiset[T]) Iterator() MapIterator {
+	return newSnapshotIterator(s.counts.all(), s.counts.len())
+}
+
+// Clear removes all the elements.
+func (s *Multiset[T]) Clear() {
+	s.counts.clear()
+	s.size = 0
+}
@@ -0,0 +1,390 @@
+package collection
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+)
+
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+// PersistentMap is an immutable hash map. Every update returns a new map sharing most of its structure
+// with the old one, so a map can be handed to other goroutines as a snapshot without copying or locking.
+// It's implemented as a hash array mapped trie (HAMT), the keys are iterated in no particular order.
+type PersistentMap[K comparable, V any] struct {
+	seed maphash.Seed
+	root *hamtNode[K, V]
+	size int
+}
+
+// TransientMap is a mutable version of a PersistentMap for batch updates. It updates in place the nodes
+// it has already copied, and is turned back into a PersistentMap by Persistent.
+// A TransientMap is not safe for concurrent use and must not be used after Persistent is called.
+type TransientMap[K comparable, V any] struct {
+	edit *owner
+	seed maphash.Seed
+	root *hamtNode[K, V]
+	size int
+}
+
+// hamtNode holds an entry for every bit set in bitmap, in the order of the bits.
+// Below the last level of the trie, the nodes hold the keys whose hashes collide in a plain list.
+type hamtNode[K comparable, V any] struct {
+	edit    *owner
+	bitmap  uint32
+	entries []hamtEntry[K, V]
+}
+
+// hamtEntry is either a key-value pair or a sub-node.
+type hamtEntry[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+	child *hamtNode[K, V]
+}
+
+// NewPersistentMap is the only way to get a new, ready-to-use PersistentMap.
+//
+// Example:
+//
+//   m := NewPersistentMap[string, int]()
+//   m2 := m.Put("a", 1) // m is still empty
+func NewPersistentMap[K comparable, V any]() *PersistentMap[K, V] {
+	return &PersistentMap[K, V]{seed: maphash.MakeSeed(), root: &hamtNode[K, V]{}}
+}
+
+// Len returns the number of entries of the map.
+func (m *PersistentMap[K, V]) Len() int {
+	return m.size
+}
+
+// Get returns the value of the key and true, or the zero value and false if the key isn't found.
+func (m *PersistentMap[K, V]) Get(key K) (V, bool) {
+	return m.root.get(0, maphash.Comparable(m.seed, key), key)
+}
+
+// Contains returns true if the map contains the key.
+func (m *PersistentMap[K, V]) Contains(key K) bool {
+	_, found := m.Get(key)
+	return found
+}
+
+// Put returns a new map where key is associated to value.
+func (m *PersistentMap[K, V]) Put(key K, value V) *PersistentMap[K, V] {
+	root, added := m.root.put(nil, 0, hamtEntry[K, V]{hash: maphash.Comparable(m.seed, key), key: key, value: value})
+	size := m.size
+	if added {
+		size++
+	}
+	return &PersistentMap[K, V]{seed: m.seed, root: root, size: size}
+}
+
+// Remove returns a new map without the key, or the map itself if it doesn't contain the key.
+func (m *PersistentMap[K, V]) Remove(key K) *PersistentMap[K, V] {
+	root, removed := m.root.remove(nil, 0, maphash.Comparable(m.seed, key), key)
+	if !removed {
+		return m
+	}
+	if root == nil {
+		root = &hamtNode[K, V]{}
+	}
+	return &PersistentMap[K, V]{seed: m.seed, root: root, size: m.size - 1}
+}
+
+// Transient returns a TransientMap with the entries of the map. The map itself isn't changed.
+func (m *PersistentMap[K, V]) Transient() *TransientMap[K, V] {
+	return &TransientMap[K, V]{edit: &owner{}, seed: m.seed, root: m.root, size: m.size}
+}
+
+// All returns an iterator over the entries of the map.
+func (m *PersistentMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.root.each(yield)
+	}
+}
+
+// Keys returns an iterator over the keys of the map.
+func (m *PersistentMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		m.root.each(func(k K, _ V) bool { return yield(k) })
+	}
+}
+
+// Iterator returns an iterator over the entries of the map.
+func (m *PersistentMap[K, V]) Iterator() MapIterator {
+	it := &hamtIterator[K, V]{}
+	it.push(m.root)
+	return it
+}
+
+// Len returns the number of entries of the map.
+func (t *TransientMap[K, V]) Len() int {
+	return t.size
+}
+
+// Get returns the value of the key and true, or the zero value and false if the key isn't found.
+func (t *TransientMap[K, V]) Get(key K) (V, bool) {
+	t.ensureEditable()
+	return t.root.get(0, maphash.Comparable(t.seed, key), key)
+}
+
+// Put associates key to value.
+func (t *TransientMap[K, V]) Put(key K, value V) *TransientMap[K, V] {
+	t.ensureEditable()
+	root, added := t.root.put(t.edit, 0, hamtEntry[K, V]{hash: maphash.Comparable(t.seed, key), key: key, value: value})
+	t.root = root
+	if added {
+		t.size++
+	}
+	return t
+}
+
+// Remove removes the key from the map.
+func (t *TransientMap[K, V]) Remove(key K) *TransientMap[K, V] {
+	t.ensureEditable()
+	root, removed := t.root.remove(t.edit, 0, maphash.Comparable(t.seed, key), key)
+	if removed {
+		if root == nil {
+			root = &hamtNode[K, V]{edit: t.edit}
+		}
+		t.root = root
+		t.size--
+	}
+	return t
+}
+
+// Persistent returns a PersistentMap with the entries of the transient map, which must not be used anymore.
+func (t *TransientMap[K, V]) Persistent() *PersistentMap[K, V] {
+	t.ensureEditable()
+	t.edit = nil
+	return &PersistentMap[K, V]{seed: t.seed, root: t.root, size: t.size}
+}
+
+func (t *TransientMap[K, V]) ensureEditable() {
+	if t.edit == nil {
+		panic("collection: TransientMap used after Persistent")
+	}
+}
+
+// editable returns node if it's owned by edit, or a copy of node owned by edit otherwise.
+func (node *hamtNode[K, V]) editable(edit *owner) *hamtNode[K, V] {
+	if edit != nil && node.edit == edit {
+		return node
+	}
+	entries := make([]hamtEntry[K, V], len(node.entries), len(node.entries)+1)
+	copy(entries, node.entries)
+	return &hamtNode[K, V]{edit: edit, bitmap: node.bitmap, entries: entries}
+}
+
+// collision returns true if the nodes at the given shift are collision lists.
+func collision(shift uint) bool {
+	return shift >= 64
+}
+
+func hamtBit(hash uint64, shift uint) uint32 {
+	return 1 << ((hash >> shift) & hamtMask)
+}
+
+func (node *hamtNode[K, V]) index(bit uint32) int {
+	return bits.OnesCount32(node.bitmap & (bit - 1))
+}
+
+func (node *hamtNode[K, V]) get(shift uint, hash uint64, key K) (V, bool) {
+	for {
+		if collision(shift) {
+			for _, e := range node.entries {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+			break
+		}
+		bit := hamtBit(hash, shift)
+		if node.bitmap&bit == 0 {
+			break
+		}
+		e := &node.entries[node.index(bit)]
+		if e.child == nil {
+			if e.key == key {
+				return e.value, true
+			}
+			break
+		}
+		node, shift = e.child, shift+hamtBits
+	}
+	var zero V
+	return zero, false
+}
+
+func (node *hamtNode[K, V]) put(edit *owner, shift uint, entry hamtEntry[K, V]) (*hamtNode[K, V], bool) {
+	if collision(shift) {
+		for i, e := range node.entries {
+			if e.key == entry.key {
+				ret := node.editable(edit)
+				ret.entries[i] = entry
+				return ret, false
+			}
+		}
+		ret := node.editable(edit)
+		ret.entries = append(ret.entries, entry)
+		return ret, true
+	}
+	bit := hamtBit(entry.hash, shift)
+	i := node.index(bit)
+	if node.bitmap&bit == 0 {
+		ret := node.editable(edit)
+		ret.entries = append(ret.entries, hamtEntry[K, V]{})
+		copy(ret.entries[i+1:], ret.entries[i:])
+		ret.entries[i] = entry
+		ret.bitmap |= bit
+		return ret, true
+	}
+	e := node.entries[i]
+	ret := node.editable(edit)
+	if e.child != nil {
+		child, added := e.child.put(edit, shift+hamtBits, entry)
+		ret.entries[i] = hamtEntry[K, V]{child: child}
+		return ret, added
+	}
+	if e.key == entry.key {
+		ret.entries[i] = entry
+		return ret, false
+	}
+	ret.entries[i] = hamtEntry[K, V]{child: mergeHamtEntries(edit, shift+hamtBits, e, entry)}
+	return ret, true
+}
+
+// mergeHamtEntries returns a node holding two entries whose hashes are equal up to the given shift.
+func mergeHamtEntries[K comparable, V any](edit *owner, shift uint, a, b hamtEntry[K, V]) *hamtNode[K, V] {
+	if collision(shift) {
+		return &hamtNode[K, V]{edit: edit, entries: []hamtEntry[K, V]{a, b}}
+	}
+	bitA, bitB := hamtBit(a.hash, shift), hamtBit(b.hash, shift)
+	if bitA == bitB {
+		child := mergeHamtEntries(edit, shift+hamtBits, a, b)
+		return &hamtNode[K, V]{edit: edit, bitmap: bitA, entries: []hamtEntry[K, V]{{child: child}}}
+	}
+	if bitA > bitB {
+		a, b = b, a
+	}
+	return &hamtNode[K, V]{edit: edit, bitmap: bitA | bitB, entries: []hamtEntry[K, V]{a, b}}
+}
+
+// remove returns the node without the key, or nil if the node becomes empty.
+func (node *hamtNode[K, V]) remove(edit *owner, shift uint, hash uint64, key K) (*hamtNode[K, V], bool) {
+	if collision(shift) {
+		for i, e := range node.entries {
+			if e.key == key {
+				if len(node.entries) == 1 {
+					return nil, true
+				}
+				ret := node.editable(edit)
+				ret.entries = append(ret.entries[:i], ret.entries[i+1:]...)
+				return ret, true
+			}
+		}
+		return node, false
+	}
+	bit := hamtBit(hash, shift)
+	if node.bitmap&bit == 0 {
+		return node, false
+	}
+	i := node.index(bit)
+	e := node.entries[i]
+	if e.child != nil {
+		child, removed := e.child.remove(edit, shift+hamtBits, hash, key)
+		if !removed {
+			return node, false
+		}
+		if child != nil {
+			ret := node.editable(edit)
+			if len(child.entries) == 1 && child.entries[0].child == nil {
+				// a single pair doesn't need a sub-node
+				ret.entries[i] = child.entries[0]
+			} else {
+				ret.entries[i] = hamtEntry[K, V]{child: child}
+			}
+			return ret, true
+		}
+	} else if e.key != key {
+		return node, false
+	}
+	if len(node.entries) == 1 {
+		return nil, true
+	}
+	ret := node.editable(edit)
+	ret.entries = append(ret.entries[:i], ret.entries[i+1:]...)
+	ret.bitmap &^= bit
+	return ret, true
+}
+
+func (node *hamtNode[K, V]) each(yield func(K, V) bool) bool {
+	for _, e := range node.entries {
+		if e.child != nil {
+			if !e.child.each(yield) {
+				return false
+			}
+		} else if !yield(e.key, e.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// hamtIterator is used for iterating a PersistentMap.
+type hamtIterator[K comparable, V any] struct {
+	stack []hamtCursor[K, V]
+	entry *hamtEntry[K, V]
+}
+
+type hamtCursor[K comparable, V any] struct {
+	node  *hamtNode[K, V]
+	index int
+}
+
+// push descends from node to its first pair.
+func (it *hamtIterator[K, V]) push(node *hamtNode[K, V]) {
+	it.stack = append(it.stack, hamtCursor[K, V]{node, 0})
+	it.advance()
+}
+
+// advance moves to the pair at the top of the stack, or to the next one if the cursor is exhausted.
+func (it *hamtIterator[K, V]) advance() {
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		if top.index >= len(top.node.entries) {
+			it.stack = it.stack[:len(it.stack)-1]
+			if len(it.stack) > 0 {
+				it.stack[len(it.stack)-1].index++
+			}
+			continue
+		}
+		e := &top.node.entries[top.index]
+		if e.child == nil {
+			it.entry = e
+			return
+		}
+		it.stack = append(it.stack, hamtCursor[K, V]{e.child, 0})
+	}
+	it.entry = nil
+}
+
+func (it *hamtIterator[K, V]) IsValid() bool {
+	return it.entry != nil
+}
+
+func (it *hamtIterator[K, V]) Next() {
+	it.stack[len(it.stack)-1].index++
+	it.advance()
+}
+
+func (it *hamtIterator[K, V]) Key() interface{} {
+	return it.entry.key
+}
+
+func (it *hamtIterator[K, V]) Value() interface{} {
+	return it.entry.value
+}
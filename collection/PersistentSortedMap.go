@@ -0,0 +1,398 @@
+package collection
+
+import (
+	"iter"
+
+	"github.com/cnfree/common/cmp"
+)
+
+// PersistentSortedMap is an immutable map ordered by its keys. Every update returns a new map sharing most
+// of its structure with the old one, only the O(log n) nodes on the path to the updated key are copied.
+// It's implemented as an AVL tree.
+type PersistentSortedMap[K, V any] struct {
+	root *avlNode[K, V]
+	size int
+	comp func(a, b K) int
+}
+
+// TransientSortedMap is a mutable version of a PersistentSortedMap for batch updates. It updates in place
+// the nodes it has already copied, and is turned back into a PersistentSortedMap by Persistent.
+// A TransientSortedMap is not safe for concurrent use and must not be used after Persistent is called.
+type TransientSortedMap[K, V any] struct {
+	edit *owner
+	root *avlNode[K, V]
+	size int
+	comp func(a, b K) int
+}
+
+type avlNode[K, V any] struct {
+	edit   *owner
+	key    K
+	value  V
+	left   *avlNode[K, V]
+	right  *avlNode[K, V]
+	height int
+}
+
+// NewPersistentSortedMap is the only way to get a new, ready-to-use PersistentSortedMap with a custom comparator.
+//
+//   comparator: for comparing keys of the map
+//
+// Example:
+//
+//   m := NewPersistentSortedMap[string, int](strings.Compare)
+func NewPersistentSortedMap[K, V any](comparator func(a, b K) int) *PersistentSortedMap[K, V] {
+	return &PersistentSortedMap[K, V]{comp: comparator}
+}
+
+// NewDefaultPersistentSortedMap returns a new, ready-to-use PersistentSortedMap whose keys are compared
+// with their natural order.
+//
+// Example:
+//
+//   m := NewDefaultPersistentSortedMap[int, string]()
+func NewDefaultPersistentSortedMap[K cmp.Ordered, V any]() *PersistentSortedMap[K, V] {
+	return &PersistentSortedMap[K, V]{comp: cmp.CompareOrdered[K]}
+}
+
+// Len returns the number of entries of the map.
+func (m *PersistentSortedMap[K, V]) Len() int {
+	return m.size
+}
+
+// Get returns the value of the key and true, or the zero value and false if the key isn't found.
+func (m *PersistentSortedMap[K, V]) Get(key K) (V, bool) {
+	return avlGet(m.root, m.comp, key)
+}
+
+// Contains returns true if the map contains the key.
+func (m *PersistentSortedMap[K, V]) Contains(key K) bool {
+	_, found := m.Get(key)
+	return found
+}
+
+// Put returns a new map where key is associated to value.
+func (m *PersistentSortedMap[K, V]) Put(key K, value V) *PersistentSortedMap[K, V] {
+	root, added := avlPut(nil, m.root, m.comp, key, value)
+	size := m.size
+	if added {
+		size++
+	}
+	return &PersistentSortedMap[K, V]{root: root, size: size, comp: m.comp}
+}
+
+// Remove returns a new map without the key, or the map itself if it doesn't contain the key.
+func (m *PersistentSortedMap[K, V]) Remove(key K) *PersistentSortedMap[K, V] {
+	root, removed := avlRemove(nil, m.root, m.comp, key)
+	if !removed {
+		return m
+	}
+	return &PersistentSortedMap[K, V]{root: root, size: m.size - 1, comp: m.comp}
+}
+
+// First returns the smallest key and its value, and false if the map is empty.
+func (m *PersistentSortedMap[K, V]) First() (K, V, bool) {
+	node := m.root
+	for node != nil && node.left != nil {
+		node = node.left
+	}
+	return node.entry()
+}
+
+// Last returns the largest key and its value, and false if the map is empty.
+func (m *PersistentSortedMap[K, V]) Last() (K, V, bool) {
+	node := m.root
+	for node != nil && node.right != nil {
+		node = node.right
+	}
+	return node.entry()
+}
+
+// Transient returns a TransientSortedMap with the entries of the map. The map itself isn't changed.
+func (m *PersistentSortedMap[K, V]) Transient() *TransientSortedMap[K, V] {
+	return &TransientSortedMap[K, V]{edit: &owner{}, root: m.root, size: m.size, comp: m.comp}
+}
+
+// All returns an iterator over the entries of the map in ascending order of keys.
+func (m *PersistentSortedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.root.each(yield)
+	}
+}
+
+// Backward returns an iterator over the entries of the map in descending order of keys.
+func (m *PersistentSortedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.root.eachReverse(yield)
+	}
+}
+
+// Keys returns an iterator over the keys of the map in ascending order.
+func (m *PersistentSortedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		m.root.each(func(k K, _ V) bool { return yield(k) })
+	}
+}
+
+// Iterator returns an iterator for iterating the map in ascending order of keys.
+func (m *PersistentSortedMap[K, V]) Iterator() MapIterator {
+	it := &avlIterator[K, V]{}
+	it.descend(m.root)
+	return it
+}
+
+// ReverseIterator returns an iterator for iterating the map in descending order of keys.
+func (m *PersistentSortedMap[K, V]) ReverseIterator() MapIterator {
+	it := &avlIterator[K, V]{reverse: true}
+	it.descend(m.root)
+	return it
+}
+
+// Len returns the number of entries of the map.
+func (t *TransientSortedMap[K, V]) Len() int {
+	return t.size
+}
+
+// Get returns the value of the key and true, or the zero value and false if the key isn't found.
+func (t *TransientSortedMap[K, V]) Get(key K) (V, bool) {
+	t.ensureEditable()
+	return avlGet(t.root, t.comp, key)
+}
+
+// Put associates key to value.
+func (t *TransientSortedMap[K, V]) Put(key K, value V) *TransientSortedMap[K, V] {
+	t.ensureEditable()
+	root, added := avlPut(t.edit, t.root, t.comp, key, value)
+	t.root = root
+	if added {
+		t.size++
+	}
+	return t
+}
+
+// Remove removes the key from the map.
+func (t *TransientSortedMap[K, V]) Remove(key K) *TransientSortedMap[K, V] {
+	t.ensureEditable()
+	root, removed := avlRemove(t.edit, t.root, t.comp, key)
+	if removed {
+		t.root = root
+		t.size--
+	}
+	return t
+}
+
+// Persistent returns a PersistentSortedMap with the entries of the transient map, which must not be used anymore.
+func (t *TransientSortedMap[K, V]) Persistent() *PersistentSortedMap[K, V] {
+	t.ensureEditable()
+	t.edit = nil
+	return &PersistentSortedMap[K, V]{root: t.root, size: t.size, comp: t.comp}
+}
+
+func (t *TransientSortedMap[K, V]) ensureEditable() {
+	if t.edit == nil {
+		panic("collection: TransientSortedMap used after Persistent")
+	}
+}
+
+// editable returns node if it's owned by edit, or a copy of node owned by edit otherwise.
+func (node *avlNode[K, V]) editable(edit *owner) *avlNode[K, V] {
+	if edit != nil && node.edit == edit {
+		return node
+	}
+	ret := *node
+	ret.edit = edit
+	return &ret
+}
+
+func (node *avlNode[K, V]) entry() (K, V, bool) {
+	if node == nil {
+		var key K
+		var value V
+		return key, value, false
+	}
+	return node.key, node.value, true
+}
+
+func avlHeight[K, V any](node *avlNode[K, V]) int {
+	if node == nil {
+		return 0
+	}
+	return node.height
+}
+
+func avlGet[K, V any](node *avlNode[K, V], comp func(a, b K) int, key K) (V, bool) {
+	for node != nil {
+		c := comp(key, node.key)
+		switch {
+		case c < 0:
+			node = node.left
+		case c > 0:
+			node = node.right
+		default:
+			return node.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+func avlPut[K, V any](edit *owner, node *avlNode[K, V], comp func(a, b K) int, key K, value V) (*avlNode[K, V], bool) {
+	if node == nil {
+		return &avlNode[K, V]{edit: edit, key: key, value: value, height: 1}, true
+	}
+	c := comp(key, node.key)
+	if c == 0 {
+		ret := node.editable(edit)
+		ret.value = value
+		return ret, false
+	}
+	var child *avlNode[K, V]
+	var added bool
+	if c < 0 {
+		child, added = avlPut(edit, node.left, comp, key, value)
+	} else {
+		child, added = avlPut(edit, node.right, comp, key, value)
+	}
+	ret := node.editable(edit)
+	if c < 0 {
+		ret.left = child
+	} else {
+		ret.right = child
+	}
+	return ret.balance(edit), added
+}
+
+func avlRemove[K, V any](edit *owner, node *avlNode[K, V], comp func(a, b K) int, key K) (*avlNode[K, V], bool) {
+	if node == nil {
+		return nil, false
+	}
+	c := comp(key, node.key)
+	if c == 0 {
+		if node.left == nil {
+			return node.right, true
+		}
+		if node.right == nil {
+			return node.left, true
+		}
+		// replace the node by its successor
+		successor := node.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		ret := node.editable(edit)
+		ret.key, ret.value = successor.key, successor.value
+		ret.right, _ = avlRemove(edit, node.right, comp, successor.key)
+		return ret.balance(edit), true
+	}
+	var child *avlNode[K, V]
+	var removed bool
+	if c < 0 {
+		child, removed = avlRemove(edit, node.left, comp, key)
+	} else {
+		child, removed = avlRemove(edit, node.right, comp, key)
+	}
+	if !removed {
+		return node, false
+	}
+	ret := node.editable(edit)
+	if c < 0 {
+		ret.left = child
+	} else {
+		ret.right = child
+	}
+	return ret.balance(edit), true
+}
+
+// balance restores the AVL invariant of an editable node whose subtrees differ in height by 2 at most.
+func (node *avlNode[K, V]) balance(edit *owner) *avlNode[K, V] {
+	diff := avlHeight(node.left) - avlHeight(node.right)
+	if diff > 1 {
+		if avlHeight(node.left.left) < avlHeight(node.left.right) {
+			node.left = node.left.editable(edit).rotateLeft(edit)
+		}
+		return node.rotateRight(edit)
+	}
+	if diff < -1 {
+		if avlHeight(node.right.right) < avlHeight(node.right.left) {
+			node.right = node.right.editable(edit).rotateRight(edit)
+		}
+		return node.rotateLeft(edit)
+	}
+	node.fixHeight()
+	return node
+}
+
+func (node *avlNode[K, V]) rotateLeft(edit *owner) *avlNode[K, V] {
+	right := node.right.editable(edit)
+	node.right = right.left
+	node.fixHeight()
+	right.left = node
+	right.fixHeight()
+	return right
+}
+
+func (node *avlNode[K, V]) rotateRight(edit *owner) *avlNode[K, V] {
+	left := node.left.editable(edit)
+	node.left = left.right
+	node.fixHeight()
+	left.right = node
+	left.fixHeight()
+	return left
+}
+
+func (node *avlNode[K, V]) fixHeight() {
+	node.height = max(avlHeight(node.left), avlHeight(node.right)) + 1
+}
+
+func (node *avlNode[K, V]) each(yield func(K, V) bool) bool {
+	if node == nil {
+		return true
+	}
+	return node.left.each(yield) && yield(node.key, node.value) && node.right.each(yield)
+}
+
+func (node *avlNode[K, V]) eachReverse(yield func(K, V) bool) bool {
+	if node == nil {
+		return true
+	}
+	return node.right.eachReverse(yield) && yield(node.key, node.value) && node.left.eachReverse(yield)
+}
+
+// avlIterator is used for iterating a PersistentSortedMap. The stack holds the ancestors not visited yet.
+type avlIterator[K, V any] struct {
+	stack   []*avlNode[K, V]
+	reverse bool
+}
+
+func (it *avlIterator[K, V]) descend(node *avlNode[K, V]) {
+	for node != nil {
+		it.stack = append(it.stack, node)
+		if it.reverse {
+			node = node.right
+		} else {
+			node = node.left
+		}
+	}
+}
+
+func (it *avlIterator[K, V]) IsValid() bool {
+	return len(it.stack) > 0
+}
+
+func (it *avlIterator[K, V]) Next() {
+	node := it.stack[len(it.stack)-1]
+	it.stack = it.stack[:len(it.stack)-1]
+	if it.reverse {
+		it.descend(node.left)
+	} else {
+		it.descend(node.right)
+	}
+}
+
+func (it *avlIterator[K, V]) Key() interface{} {
+	return it.stack[len(it.stack)-1].key
+}
+
+func (it *avlIterator[K, V]) Value() interface{} {
+	return it.stack[len(it.stack)-1].value
+}
@@ -0,0 +1,399 @@
+package collection
+
+import "iter"
+
+const (
+	vectorBits  = 5
+	vectorWidth = 1 << vectorBits
+	vectorMask  = vectorWidth - 1
+)
+
+// owner marks the nodes created by a transient collection, which it may then update in place.
+// Nodes of persistent collections have no owner and are never modified.
+type owner struct {
+	_ byte // non-zero size, so that every owner has its own address
+}
+
+// PersistentVector is an immutable indexed sequence. Every update returns a new vector sharing most of
+// its structure with the old one, so updates cost O(log32 n) instead of a full copy, and a vector can be
+// handed to other goroutines without locking.
+// The elements are stored in a 32-way trie, with the last elements kept in a tail for fast appends.
+type PersistentVector[T any] struct {
+	trie vectorTrie[T]
+}
+
+// TransientVector is a mutable version of a PersistentVector for batch updates. It updates in place the
+// nodes it has already copied, and is turned back into a PersistentVector by Persistent.
+// A TransientVector is not safe for concurrent use and must not be used after Persistent is called.
+type TransientVector[T any] struct {
+	edit *owner
+	trie vectorTrie[T]
+}
+
+type vectorTrie[T any] struct {
+	count int
+	shift uint
+	root  *vectorNode[T]
+	tail  []T
+}
+
+type vectorNode[T any] struct {
+	edit     *owner
+	children []*vectorNode[T] // for branches
+	values   []T              // for leaves
+}
+
+// NewPersistentVector is the only way to get a new, ready-to-use PersistentVector.
+//
+// Example:
+//
+//   v := NewPersistentVector[int]().Append(1).Append(2)
+//   v2 := v.Set(0, 3) // v is still [1 2]
+func NewPersistentVector[T any](values ...T) *PersistentVector[T] {
+	v := &PersistentVector[T]{vectorTrie[T]{shift: vectorBits, root: newVectorBranch[T](nil)}}
+	if len(values) == 0 {
+		return v
+	}
+	t := v.Transient()
+	for _, value := range values {
+		t.Append(value)
+	}
+	return t.Persistent()
+}
+
+// Len returns the number of elements of the vector.
+func (v *PersistentVector[T]) Len() int {
+	return v.trie.count
+}
+
+// Get returns the element at index i and true, or the zero value and false if i is out of range.
+func (v *PersistentVector[T]) Get(i int) (T, bool) {
+	return v.trie.get(i)
+}
+
+// Append returns a new vector with value added at the end.
+func (v *PersistentVector[T]) Append(value T) *PersistentVector[T] {
+	trie := v.trie
+	trie.append(nil, value)
+	return &PersistentVector[T]{trie}
+}
+
+// Set returns a new vector with the element at index i replaced by value.
+// It panics if i is out of range.
+func (v *PersistentVector[T]) Set(i int, value T) *PersistentVector[T] {
+	trie := v.trie
+	trie.set(nil, i, value)
+	return &PersistentVector[T]{trie}
+}
+
+// Pop returns a new vector without the last element, or the vector itself if it's empty.
+func (v *PersistentVector[T]) Pop() *PersistentVector[T] {
+	if v.trie.count == 0 {
+		return v
+	}
+	trie := v.trie
+	trie.pop(nil)
+	return &PersistentVector[T]{trie}
+}
+
+// Transient returns a TransientVector with the elements of the vector. The vector itself isn't changed.
+func (v *PersistentVector[T]) Transient() *TransientVector[T] {
+	trie := v.trie
+	trie.tail = make([]T, len(v.trie.tail), vectorWidth)
+	copy(trie.tail, v.trie.tail)
+	return &TransientVector[T]{edit: &owner{}, trie: trie}
+}
+
+// Slice returns the elements of the vector as a new slice.
+func (v *PersistentVector[T]) Slice() []T {
+	out := make([]T, 0, v.trie.count)
+	for _, value := range v.All() {
+		out = append(out, value)
+	}
+	return out
+}
+
+// All returns an iterator over the indexes and elements of the vector.
+func (v *PersistentVector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < v.trie.count; i += vectorWidth {
+			for j, value := range v.trie.leafFor(i) {
+				if !yield(i+j, value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Iterator returns an iterator over the vector, whose keys are the indexes of the elements.
+func (v *PersistentVector[T]) Iterator() MapIterator {
+	it := &vectorIterator[T]{trie: v.trie}
+	if v.trie.count > 0 {
+		it.leaf = v.trie.leafFor(0)
+	}
+	return it
+}
+
+// Len returns the number of elements of the vector.
+func (t *TransientVector[T]) Len() int {
+	return t.trie.count
+}
+
+// Get returns the element at index i and true, or the zero value and false if i is out of range.
+func (t *TransientVector[T]) Get(i int) (T, bool) {
+	t.ensureEditable()
+	return t.trie.get(i)
+}
+
+// Append adds value at the end of the vector.
+func (t *TransientVector[T]) Append(value T) *TransientVector[T] {
+	t.ensureEditable()
+	t.trie.append(t.edit, value)
+	return t
+}
+
+// Set replaces the element at index i. It panics if i is out of range.
+func (t *TransientVector[T]) Set(i int, value T) *TransientVector[T] {
+	t.ensureEditable()
+	t.trie.set(t.edit, i, value)
+	return t
+}
+
+// Pop removes the last element of the vector, if any.
+func (t *TransientVector[T]) Pop() *TransientVector[T] {
+	t.ensureEditable()
+	if t.trie.count > 0 {
+		t.trie.pop(t.edit)
+	}
+	return t
+}
+
+// Persistent returns a PersistentVector with the elements of the transient vector, which must not be used anymore.
+func (t *TransientVector[T]) Persistent() *PersistentVector[T] {
+	t.ensureEditable()
+	t.edit = nil
+	trie := t.trie
+	trie.tail = make([]T, len(t.trie.tail))
+	copy(trie.tail, t.trie.tail)
+	return &PersistentVector[T]{trie}
+}
+
+func (t *TransientVector[T]) ensureEditable() {
+	if t.edit == nil {
+		panic("collection: TransientVector used after Persistent")
+	}
+}
+
+func newVectorBranch[T any](edit *owner) *vectorNode[T] {
+	return &vectorNode[T]{edit: edit, children: make([]*vectorNode[T], vectorWidth)}
+}
+
+// editable returns node if it's owned by edit, or a copy of node owned by edit otherwise.
+// A nil edit always copies, which is how the persistent updates are done.
+func (node *vectorNode[T]) editable(edit *owner) *vectorNode[T] {
+	if edit != nil && node.edit == edit {
+		return node
+	}
+	ret := &vectorNode[T]{edit: edit}
+	if node.children != nil {
+		ret.children = make([]*vectorNode[T], vectorWidth)
+		copy(ret.children, node.children)
+	} else {
+		ret.values = make([]T, len(node.values))
+		copy(ret.values, node.values)
+	}
+	return ret
+}
+
+func (trie *vectorTrie[T]) tailOffset() int {
+	if trie.count < vectorWidth {
+		return 0
+	}
+	return ((trie.count - 1) >> vectorBits) << vectorBits
+}
+
+// leafFor returns the leaf or the tail holding the element at index i.
+func (trie *vectorTrie[T]) leafFor(i int) []T {
+	if i >= trie.tailOffset() {
+		return trie.tail
+	}
+	node := trie.root
+	for level := trie.shift; level > 0; level -= vectorBits {
+		node = node.children[(i>>level)&vectorMask]
+	}
+	return node.values
+}
+
+func (trie *vectorTrie[T]) get(i int) (T, bool) {
+	if i < 0 || i >= trie.count {
+		var zero T
+		return zero, false
+	}
+	return trie.leafFor(i)[i&vectorMask], true
+}
+
+func (trie *vectorTrie[T]) append(edit *owner, value T) {
+	if trie.count-trie.tailOffset() < vectorWidth {
+		if edit != nil {
+			trie.tail = append(trie.tail, value)
+		} else {
+			tail := make([]T, len(trie.tail)+1)
+			copy(tail, trie.tail)
+			tail[len(trie.tail)] = value
+			trie.tail = tail
+		}
+		trie.count++
+		return
+	}
+	// the tail is full, push it into the trie
+	tailNode := &vectorNode[T]{edit: edit, values: trie.tail}
+	if (trie.count >> vectorBits) > (1 << trie.shift) {
+		// the trie is full, add a level
+		root := newVectorBranch[T](edit)
+		root.children[0] = trie.root
+		root.children[1] = newVectorPath(edit, trie.shift, tailNode)
+		trie.root = root
+		trie.shift += vectorBits
+	} else {
+		trie.root = trie.pushTail(edit, trie.shift, trie.root, tailNode)
+	}
+	if edit != nil {
+		trie.tail = make([]T, 1, vectorWidth)
+		trie.tail[0] = value
+	} else {
+		trie.tail = []T{value}
+	}
+	trie.count++
+}
+
+func newVectorPath[T any](edit *owner, level uint, node *vectorNode[T]) *vectorNode[T] {
+	if level == 0 {
+		return node
+	}
+	ret := newVectorBranch[T](edit)
+	ret.children[0] = newVectorPath(edit, level-vectorBits, node)
+	return ret
+}
+
+func (trie *vectorTrie[T]) pushTail(edit *owner, level uint, parent, tailNode *vectorNode[T]) *vectorNode[T] {
+	ret := parent.editable(edit)
+	i := ((trie.count - 1) >> level) & vectorMask
+	if level == vectorBits {
+		ret.children[i] = tailNode
+	} else if child := parent.children[i]; child != nil {
+		ret.children[i] = trie.pushTail(edit, level-vectorBits, child, tailNode)
+	} else {
+		ret.children[i] = newVectorPath(edit, level-vectorBits, tailNode)
+	}
+	return ret
+}
+
+func (trie *vectorTrie[T]) set(edit *owner, i int, value T) {
+	if i < 0 || i >= trie.count {
+		panic("collection: vector index out of range")
+	}
+	if i >= trie.tailOffset() {
+		if edit == nil {
+			tail := make([]T, len(trie.tail))
+			copy(tail, trie.tail)
+			trie.tail = tail
+		}
+		trie.tail[i&vectorMask] = value
+		return
+	}
+	trie.root = trie.assoc(edit, trie.shift, trie.root, i, value)
+}
+
+func (trie *vectorTrie[T]) assoc(edit *owner, level uint, node *vectorNode[T], i int, value T) *vectorNode[T] {
+	ret := node.editable(edit)
+	if level == 0 {
+		ret.values[i&vectorMask] = value
+	} else {
+		j := (i >> level) & vectorMask
+		ret.children[j] = trie.assoc(edit, level-vectorBits, node.children[j], i, value)
+	}
+	return ret
+}
+
+func (trie *vectorTrie[T]) pop(edit *owner) {
+	if trie.count == 1 {
+		*trie = vectorTrie[T]{shift: vectorBits, root: newVectorBranch[T](edit)}
+		if edit != nil {
+			trie.tail = make([]T, 0, vectorWidth)
+		}
+		return
+	}
+	if trie.count-trie.tailOffset() > 1 {
+		var zero T
+		if edit != nil {
+			trie.tail[len(trie.tail)-1] = zero // release the element
+		}
+		trie.tail = trie.tail[:len(trie.tail)-1]
+		trie.count--
+		return
+	}
+	// the tail becomes empty, pull the last leaf out of the trie
+	tail := trie.leafFor(trie.count - 2)
+	if edit != nil {
+		owned := make([]T, len(tail), vectorWidth)
+		copy(owned, tail)
+		tail = owned
+	}
+	root := trie.popTail(edit, trie.shift, trie.root)
+	shift := trie.shift
+	if root == nil {
+		root = newVectorBranch[T](edit)
+	}
+	if shift > vectorBits && root.children[1] == nil {
+		root = root.children[0]
+		shift -= vectorBits
+	}
+	trie.root, trie.shift, trie.tail = root, shift, tail
+	trie.count--
+}
+
+func (trie *vectorTrie[T]) popTail(edit *owner, level uint, node *vectorNode[T]) *vectorNode[T] {
+	i := ((trie.count - 2) >> level) & vectorMask
+	if level > vectorBits {
+		child := trie.popTail(edit, level-vectorBits, node.children[i])
+		if child == nil && i == 0 {
+			return nil
+		}
+		ret := node.editable(edit)
+		ret.children[i] = child
+		return ret
+	}
+	if i == 0 {
+		return nil
+	}
+	ret := node.editable(edit)
+	ret.children[i] = nil
+	return ret
+}
+
+// vectorIterator is used for iterating a PersistentVector.
+type vectorIterator[T any] struct {
+	trie  vectorTrie[T]
+	index int
+	leaf  []T
+}
+
+func (it *vectorIterator[T]) IsValid() bool {
+	return it.index < it.trie.count
+}
+
+func (it *vectorIterator[T]) Next() {
+	it.index++
+	if it.index < it.trie.count && it.index&vectorMask == 0 {
+		it.leaf = it.trie.leafFor(it.index)
+	}
+}
+
+func (it *vectorIterator[T]) Key() interface{} {
+	return it.index
+}
+
+func (it *vectorIterator[T]) Value() interface{} {
+	return it.leaf[it.index&vectorMask]
+}
@@ -0,0 +1,213 @@
+package collection
+
+import (
+	"iter"
+
+	"github.com/cnfree/common/cmp"
+)
+
+// PriorityQueue is a binary heap whose first element is the smallest one according to its comparator.
+// Push returns a handle to the element, which can then be updated or removed in O(log n), such as for
+// the decrease-key operation of Dijkstra's algorithm.
+// A PriorityQueue is not safe for concurrent use.
+type PriorityQueue[T any] struct {
+	items []*PriorityQueueHandle[T]
+	comp  func(a, b T) int
+}
+
+// PriorityQueueHandle is a reference to an element of a PriorityQueue.
+type PriorityQueueHandle[T any] struct {
+	value T
+	index int // position in the heap, -1 once the element is removed
+}
+
+// Value returns the element referenced by the handle.
+func (h *PriorityQueueHandle[T]) Value() T {
+	return h.value
+}
+
+// Queued returns true if the element is still in its queue.
+func (h *PriorityQueueHandle[T]) Queued() bool {
+	return h.index >= 0
+}
+
+// NewPriorityQueue is the only way to get a new, ready-to-use PriorityQueue with a custom comparator.
+//
+//   comparator: for comparing the elements, the smallest one is at the head of the queue
+//
+// Example:
+//
+//   pq := collection.NewPriorityQueue[*Task](func(a, b *Task) int { return b.Priority - a.Priority })
+//   h := pq.Push(task)
+//   task.Priority = 10
+//   pq.Fix(h)
+func NewPriorityQueue[T any](comparator func(a, b T) int) *PriorityQueue[T] {
+	return &PriorityQueue[T]{comp: comparator}
+}
+
+// NewDefaultPriorityQueue returns a new, ready-to-use PriorityQueue whose smallest element in natural
+// order is at the head.
+//
+// Example:
+//
+//   pq := collection.NewDefaultPriorityQueue[int]()
+func NewDefaultPriorityQueue[T cmp.Ordered]() *PriorityQueue[T] {
+	return &PriorityQueue[T]{comp: cmp.CompareOrdered[T]}
+}
+
+// Len returns the number of elements in the queue.
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items)
+}
+
+// Empty returns true if the queue has no elements.
+func (pq *PriorityQueue[T]) Empty() bool {
+	return len(pq.items) == 0
+}
+
+// Push adds an element to the queue and returns its handle.
+func (pq *PriorityQueue[T]) Push(value T) *PriorityQueueHandle[T] {
+	h := &PriorityQueueHandle[T]{value: value, index: len(pq.items)}
+	pq.items = append(pq.items, h)
+	pq.up(h.index)
+	return h
+}
+
+// Peek returns the head of the queue and true, or the zero value and false if the queue is empty.
+func (pq *PriorityQueue[T]) Peek() (T, bool) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return pq.items[0].value, true
+}
+
+// PeekHandle returns the handle of the head of the queue, or nil if the queue is empty.
+func (pq *PriorityQueue[T]) PeekHandle() *PriorityQueueHandle[T] {
+	if len(pq.items) == 0 {
+		return nil
+	}
+	return pq.items[0]
+}
+
+// Pop removes the head of the queue and returns it, or returns the zero value and false if the queue is empty.
+func (pq *PriorityQueue[T]) Pop() (T, bool) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return pq.Remove(pq.items[0])
+}
+
+// Update replaces the element referenced by h and restores its position in the queue.
+// It returns false if the element isn't in the queue anymore.
+func (pq *PriorityQueue[T]) Update(h *PriorityQueueHandle[T], value T) bool {
+	if !pq.owns(h) {
+		return false
+	}
+	h.value = value
+	pq.fix(h.index)
+	return true
+}
+
+// Fix restores the position in the queue of the element referenced by h after its priority changed,
+// such as when the element is a pointer to a struct whose priority field was modified.
+// It returns false if the element isn't in the queue anymore.
+func (pq *PriorityQueue[T]) Fix(h *PriorityQueueHandle[T]) bool {
+	if !pq.owns(h) {
+		return false
+	}
+	pq.fix(h.index)
+	return true
+}
+
+// Remove removes the element referenced by h from the queue and returns it, or returns the zero value
+// and false if it isn't in the queue anymore.
+func (pq *PriorityQueue[T]) Remove(h *PriorityQueueHandle[T]) (T, bool) {
+	if !pq.owns(h) {
+		var zero T
+		return zero, false
+	}
+	i, last := h.index, len(pq.items)-1
+	if i != last {
+		pq.swap(i, last)
+	}
+	pq.items[last] = nil
+	pq.items = pq.items[:last]
+	if i != last {
+		pq.fix(i)
+	}
+	h.index = -1
+	return h.value, true
+}
+
+// Clear removes all the elements from the queue.
+func (pq *PriorityQueue[T]) Clear() {
+	for _, h := range pq.items {
+		h.index = -1
+	}
+	pq.items = nil
+}
+
+// All returns an iterator over the elements of the queue, in no particular order.
+func (pq *PriorityQueue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, h := range pq.items {
+			if !yield(h.value) {
+				return
+			}
+		}
+	}
+}
+
+// owns returns true if h references an element of this queue.
+func (pq *PriorityQueue[T]) owns(h *PriorityQueueHandle[T]) bool {
+	return h != nil && h.index >= 0 && h.index < len(pq.items) && pq.items[h.index] == h
+}
+
+func (pq *PriorityQueue[T]) less(i, j int) bool {
+	return pq.comp(pq.items[i].value, pq.items[j].value) < 0
+}
+
+func (pq *PriorityQueue[T]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
+}
+
+func (pq *PriorityQueue[T]) fix(i int) {
+	if !pq.down(i) {
+		pq.up(i)
+	}
+}
+
+func (pq *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(i, parent) {
+			break
+		}
+		pq.swap(i, parent)
+		i = parent
+	}
+}
+
+// down moves the element at i down the heap and returns true if it moved.
+func (pq *PriorityQueue[T]) down(i int) bool {
+	start, n := i, len(pq.items)
+	for {
+		child := 2*i + 1
+		if child >= n {
+			break
+		}
+		if right := child + 1; right < n && pq.less(right, child) {
+			child = right
+		}
+		if !pq.less(child, i) {
+			break
+		}
+		pq.swap(i, child)
+		i = child
+	}
+	return i > start
+}
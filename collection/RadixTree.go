@@ -0,0 +1,246 @@
+package collection
+
+import (
+	"iter"
+	"sort"
+)
+
+// RadixTree is a map from strings to values stored as a compressed trie: the keys sharing a prefix share
+// the nodes of that prefix, and the chains of nodes with a single child are merged into one node.
+// Besides exact lookups, it finds the longest key prefixing a string, such as the route of a URL path,
+// and iterates over the keys having a prefix. The keys are iterated in ascending byte-wise order.
+// A RadixTree is not safe for concurrent use.
+type RadixTree[V any] struct {
+	root radixNode[V]
+	size int
+}
+
+type radixNode[V any] struct {
+	prefix   string // label of the edge from the parent
+	leaf     bool   // true if the node holds a value
+	value    V
+	children []*radixNode[V] // sorted by the first byte of their prefixes
+}
+
+// NewRadixTree is the only way to get a new, ready-to-use RadixTree.
+//
+// Example:
+//
+//   routes := collection.NewRadixTree[http.Handler]()
+//   routes.Put("/api/", apiHandler)
+//   routes.Put("/api/users/", usersHandler)
+//   prefix, handler, ok := routes.LongestPrefix("/api/users/42") // "/api/users/", usersHandler, true
+func NewRadixTree[V any]() *RadixTree[V] {
+	return &RadixTree[V]{}
+}
+
+// Len returns the number of keys of the tree.
+func (t *RadixTree[V]) Len() int {
+	return t.size
+}
+
+// Get returns the value of the key and true, or the zero value and false if the key isn't found.
+func (t *RadixTree[V]) Get(key string) (V, bool) {
+	n := &t.root
+	for {
+		if key == "" {
+			if n.leaf {
+				return n.value, true
+			}
+			break
+		}
+		child, _ := n.child(key[0])
+		if child == nil || len(key) < len(child.prefix) || key[:len(child.prefix)] != child.prefix {
+			break
+		}
+		n, key = child, key[len(child.prefix):]
+	}
+	var zero V
+	return zero, false
+}
+
+// Put associates the key to value and returns true if the key was added, false if its value was replaced.
+func (t *RadixTree[V]) Put(key string, value V) bool {
+	n := &t.root
+	for key != "" {
+		child, i := n.child(key[0])
+		if child == nil {
+			n.insertChild(i, &radixNode[V]{prefix: key, leaf: true, value: value})
+			t.size++
+			return true
+		}
+		common := commonPrefixLen(key, child.prefix)
+		if common < len(child.prefix) {
+			// splits the edge to the child
+			mid := &radixNode[V]{prefix: child.prefix[:common], children: []*radixNode[V]{child}}
+			child.prefix = child.prefix[common:]
+			n.children[i] = mid
+			child = mid
+		}
+		n, key = child, key[common:]
+	}
+	added := !n.leaf
+	if added {
+		t.size++
+	}
+	n.leaf, n.value = true, value
+	return added
+}
+
+// Delete removes the key from the tree and returns true, or returns false if the key wasn't found.
+func (t *RadixTree[V]) Delete(key string) bool {
+	var parent *radixNode[V]
+	var index int
+	n := &t.root
+	for key != "" {
+		child, i := n.child(key[0])
+		if child == nil || len(key) < len(child.prefix) || key[:len(child.prefix)] != child.prefix {
+			return false
+		}
+		parent, index, n, key = n, i, child, key[len(child.prefix):]
+	}
+	if !n.leaf {
+		return false
+	}
+	var zero V
+	n.leaf, n.value = false, zero
+	t.size--
+	if parent == nil {
+		return true
+	}
+	switch len(n.children) {
+	case 0:
+		parent.children = append(parent.children[:index], parent.children[index+1:]...)
+		if parent != &t.root && !parent.leaf && len(parent.children) == 1 {
+			parent.mergeChild()
+		}
+	case 1:
+		n.mergeChild()
+	}
+	return true
+}
+
+// LongestPrefix returns the longest key of the tree prefixing s, and its value.
+// It returns false if no key prefixes s.
+func (t *RadixTree[V]) LongestPrefix(s string) (string, V, bool) {
+	var found *radixNode[V]
+	var length, consumed int
+	n := &t.root
+	for {
+		if n.leaf {
+			found, length = n, consumed
+		}
+		if consumed == len(s) {
+			break
+		}
+		child, _ := n.child(s[consumed])
+		if child == nil || !hasPrefixAt(s, consumed, child.prefix) {
+			break
+		}
+		n, consumed = child, consumed+len(child.prefix)
+	}
+	if found == nil {
+		var zero V
+		return "", zero, false
+	}
+	return s[:length], found.value, true
+}
+
+// All returns an iterator over the entries of the tree in ascending key order.
+func (t *RadixTree[V]) All() iter.Seq2[string, V] {
+	return t.Prefix("")
+}
+
+// Keys returns an iterator over the keys of the tree in ascending order.
+func (t *RadixTree[V]) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for k := range t.Prefix("") {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Prefix returns an iterator over the entries whose keys start with prefix, in ascending key order.
+func (t *RadixTree[V]) Prefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		n := &t.root
+		consumed := 0
+		for consumed < len(prefix) {
+			child, _ := n.child(prefix[consumed])
+			if child == nil {
+				return
+			}
+			rest := prefix[consumed:]
+			if len(rest) <= len(child.prefix) {
+				// the prefix ends inside the edge to the child
+				if child.prefix[:len(rest)] != rest {
+					return
+				}
+			} else if rest[:len(child.prefix)] != child.prefix {
+				return
+			}
+			n, consumed = child, consumed+len(child.prefix)
+		}
+		key := make([]byte, 0, 64)
+		key = append(key, prefix[:consumed-len(n.prefix)]...)
+		n.each(key, yield)
+	}
+}
+
+// Clear removes all the keys from the tree.
+func (t *RadixTree[V]) Clear() {
+	t.root = radixNode[V]{}
+	t.size = 0
+}
+
+// child returns the child whose prefix starts with c, or nil and the position where to insert it.
+func (n *radixNode[V]) child(c byte) (*radixNode[V], int) {
+	i := sort.Search(len(n.children), func(i int) bool { return n.children[i].prefix[0] >= c })
+	if i < len(n.children) && n.children[i].prefix[0] == c {
+		return n.children[i], i
+	}
+	return nil, i
+}
+
+func (n *radixNode[V]) insertChild(i int, child *radixNode[V]) {
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = child
+}
+
+// mergeChild merges a node without value into its only child.
+func (n *radixNode[V]) mergeChild() {
+	child := n.children[0]
+	n.prefix += child.prefix
+	n.leaf, n.value, n.children = child.leaf, child.value, child.children
+}
+
+// each yields the entries of the subtree of n, key being the key of the parent of n.
+func (n *radixNode[V]) each(key []byte, yield func(string, V) bool) bool {
+	key = append(key, n.prefix...)
+	if n.leaf && !yield(string(key), n.value) {
+		return false
+	}
+	for _, child := range n.children {
+		if !child.each(key, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+func commonPrefixLen(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+func hasPrefixAt(s string, i int, prefix string) bool {
+	return len(s)-i >= len(prefix) && s[i:i+len(prefix)] == prefix
+}
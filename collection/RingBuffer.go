@@ -0,0 +1,166 @@
+package collection
+
+import (
+	"fmt"
+	"iter"
+)
+
+// FullPolicy is the behavior of a RingBuffer when an element is pushed while it's full.
+type FullPolicy int
+
+const (
+	// Overwrite drops the oldest element to make room for the new one.
+	Overwrite FullPolicy = iota
+	// Reject drops the new element.
+	Reject
+)
+
+// RingBuffer is a FIFO buffer of a fixed capacity, which never allocates after its creation.
+// The elements are indexed from the oldest one, at 0, to the newest one, at Len()-1.
+// A RingBuffer is not safe for concurrent use.
+type RingBuffer[T any] struct {
+	buf    []T
+	head   int // index of the oldest element in buf
+	size   int
+	policy FullPolicy
+}
+
+// NewRingBuffer is the only way to get a new, ready-to-use RingBuffer.
+//
+//   capacity: maximum number of elements in the buffer, must be positive
+//   policy: what happens when an element is pushed while the buffer is full
+//
+// Example:
+//
+//   last := collection.NewRingBuffer[string](100, collection.Overwrite)
+//   last.Push(line)
+//   for _, line := range last.All() {
+//       fmt.Println(line)
+//   }
+func NewRingBuffer[T any](capacity int, policy FullPolicy) *RingBuffer[T] {
+	if capacity <= 0 {
+		panic(fmt.Errorf("invalid RingBuffer capacity: %d", capacity))
+	}
+	return &RingBuffer[T]{buf: make([]T, capacity), policy: policy}
+}
+
+// Push adds an element after the newest one. It returns false if the buffer is full and its policy is Reject.
+func (rb *RingBuffer[T]) Push(value T) bool {
+	if rb.size == len(rb.buf) {
+		if rb.policy == Reject {
+			return false
+		}
+		rb.buf[rb.head] = value
+		rb.head = rb.index(1)
+		return true
+	}
+	rb.buf[rb.index(rb.size)] = value
+	rb.size++
+	return true
+}
+
+// Pop removes the oldest element and returns it, or returns the zero value and false if the buffer is empty.
+func (rb *RingBuffer[T]) Pop() (T, bool) {
+	var zero T
+	if rb.size == 0 {
+		return zero, false
+	}
+	value := rb.buf[rb.head]
+	rb.buf[rb.head] = zero
+	rb.head = rb.index(1)
+	rb.size--
+	return value, true
+}
+
+// PopBack removes the newest element and returns it, or returns the zero value and false if the buffer is empty.
+func (rb *RingBuffer[T]) PopBack() (T, bool) {
+	var zero T
+	if rb.size == 0 {
+		return zero, false
+	}
+	i := rb.index(rb.size - 1)
+	value := rb.buf[i]
+	rb.buf[i] = zero
+	rb.size--
+	return value, true
+}
+
+// Peek returns the oldest element, or the zero value and false if the buffer is empty.
+func (rb *RingBuffer[T]) Peek() (T, bool) {
+	return rb.Get(0)
+}
+
+// PeekBack returns the newest element, or the zero value and false if the buffer is empty.
+func (rb *RingBuffer[T]) PeekBack() (T, bool) {
+	return rb.Get(rb.size - 1)
+}
+
+// Get returns the element at index i, 0 being the oldest one, or the zero value and false if i is out of range.
+func (rb *RingBuffer[T]) Get(i int) (T, bool) {
+	if i < 0 || i >= rb.size {
+		var zero T
+		return zero, false
+	}
+	return rb.buf[rb.index(i)], true
+}
+
+// Len returns the number of elements in the buffer.
+func (rb *RingBuffer[T]) Len() int {
+	return rb.size
+}
+
+// Cap returns the capacity of the buffer.
+func (rb *RingBuffer[T]) Cap() int {
+	return len(rb.buf)
+}
+
+// Empty returns true if the buffer has no elements.
+func (rb *RingBuffer[T]) Empty() bool {
+	return rb.size == 0
+}
+
+// Full returns true if the buffer holds Cap() elements.
+func (rb *RingBuffer[T]) Full() bool {
+	return rb.size == len(rb.buf)
+}
+
+// Clear removes all the elements from the buffer.
+func (rb *RingBuffer[T]) Clear() {
+	clear(rb.buf)
+	rb.head, rb.size = 0, 0
+}
+
+// All returns an iterator over the indexes and elements of the buffer, from the oldest to the newest.
+func (rb *RingBuffer[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < rb.size; i++ {
+			if !yield(i, rb.buf[rb.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Slice returns the elements of the buffer as a new slice, from the oldest to the newest.
+func (rb *RingBuffer[T]) Slice() []T {
+	return rb.AppendTo(make([]T, 0, rb.size))
+}
+
+// AppendTo appends the elements of the buffer to s, from the oldest to the newest, and returns the extended slice.
+func (rb *RingBuffer[T]) AppendTo(s []T) []T {
+	end := rb.head + rb.size
+	if end <= len(rb.buf) {
+		return append(s, rb.buf[rb.head:end]...)
+	}
+	s = append(s, rb.buf[rb.head:]...)
+	return append(s, rb.buf[:end-len(rb.buf)]...)
+}
+
+// index returns the position in buf of the i-th element.
+func (rb *RingBuffer[T]) index(i int) int {
+	i += rb.head
+	if i >= len(rb.buf) {
+		i -= len(rb.buf)
+	}
+	return i
+}
@@ -0,0 +1,183 @@
+package collection
+
+import (
+	"hash/maphash"
+	"sync"
+	"time"
+
+	"github.com/cnfree/common/cmp"
+)
+
+// NewShardedCache is the only way to get a new, ready-to-use ShardedCache with a custom key comparator.
+// MaxEntries and MaxCost of the options are split evenly among the shards,
+// so the eviction is only approximately global.
+//
+//   shards: number of independently locked Caches, typically a small multiple of GOMAXPROCS
+//
+// Example:
+//
+//   cache := collection.NewShardedCache[string, *User](16, strings.Compare, collection.CacheOptions[string, *User]{
+//       Policy:     collection.LFU,
+//       MaxEntries: 100000,
+//   })
+func NewShardedCache[K comparable, V any](shards int, comparator func(a, b K) int, options CacheOptions[K, V]) *ShardedCache[K, V] {
+	if shards <= 0 {
+		shards = 1
+	}
+	shardOptions := options
+	if options.MaxEntries > 0 {
+		shardOptions.MaxEntries = (options.MaxEntries + shards - 1) / shards
+	}
+	if options.MaxCost > 0 {
+		shardOptions.MaxCost = (options.MaxCost + int64(shards) - 1) / int64(shards)
+	}
+	sc := &ShardedCache[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]cacheShard[K, V], shards),
+	}
+	for i := range sc.shards {
+		sc.shards[i].cache = NewCache[K, V](comparator, shardOptions)
+	}
+	return sc
+}
+
+// NewDefaultShardedCache returns a new, ready-to-use ShardedCache whose keys are compared with their natural order.
+func NewDefaultShardedCache[K cmp.Ordered, V any](shards int, options CacheOptions[K, V]) *ShardedCache[K, V] {
+	return NewShardedCache[K, V](shards, cmp.CompareOrdered[K], options)
+}
+
+// ShardedCache is a goroutine-safe Cache: the keys are spread by hash over several Caches, each guarded
+// by its own lock. The OnEvict callback is called with the lock of the shard held, it mustn't call
+// the ShardedCache back.
+type ShardedCache[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []cacheShard[K, V]
+}
+
+type cacheShard[K, V any] struct {
+	lock  sync.Mutex
+	cache *Cache[K, V]
+	_     [40]byte // padding to avoid false sharing
+}
+
+// Get returns the value of the key and true if the key is cached and not expired.
+func (sc *ShardedCache[K, V]) Get(key K) (V, bool) {
+	s := sc.shard(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.cache.Get(key)
+}
+
+// Peek is the same as Get, except that it neither counts as an access nor updates the stats.
+func (sc *ShardedCache[K, V]) Peek(key K) (V, bool) {
+	s := sc.shard(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.cache.Peek(key)
+}
+
+// Put adds or updates an entry with the default TTL.
+func (sc *ShardedCache[K, V]) Put(key K, value V) {
+	s := sc.shard(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.cache.Put(key, value)
+}
+
+// PutWithTTL adds or updates an entry with a specific TTL. A ttl of 0 means the entry never expires.
+func (sc *ShardedCache[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	s := sc.shard(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.cache.PutWithTTL(key, value, ttl)
+}
+
+// GetOrLoad returns the cached value of the key, or calls load, caches and returns its result.
+// load is called with the lock of the shard held, use a task.SingleFlight in front of the cache
+// for slow loads.
+func (sc *ShardedCache[K, V]) GetOrLoad(key K, load func(K) (V, error)) (V, error) {
+	s := sc.shard(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if v, ok := s.cache.Get(key); ok {
+		return v, nil
+	}
+	v, err := load(key)
+	if err != nil {
+		return v, err
+	}
+	s.cache.Put(key, v)
+	return v, nil
+}
+
+// Remove removes the entry of the key.
+// Return value: true if the key was cached.
+func (sc *ShardedCache[K, V]) Remove(key K) bool {
+	s := sc.shard(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.cache.Remove(key)
+}
+
+// RemoveExpired removes all the expired entries of all the shards.
+// Return value: the number of removed entries.
+func (sc *ShardedCache[K, V]) RemoveExpired() int {
+	n := 0
+	for i := range sc.shards {
+		s := &sc.shards[i]
+		s.lock.Lock()
+		n += s.cache.RemoveExpired()
+		s.lock.Unlock()
+	}
+	return n
+}
+
+// Clear removes all the entries of all the shards.
+func (sc *ShardedCache[K, V]) Clear() {
+	for i := range sc.shards {
+		s := &sc.shards[i]
+		s.lock.Lock()
+		s.cache.Clear()
+		s.lock.Unlock()
+	}
+}
+
+// Len returns the number of entries of all the shards.
+func (sc *ShardedCache[K, V]) Len() int {
+	n := 0
+	for i := range sc.shards {
+		s := &sc.shards[i]
+		s.lock.Lock()
+		n += s.cache.Len()
+		s.lock.Unlock()
+	}
+	return n
+}
+
+// Cost returns the total cost of the entries of all the shards.
+func (sc *ShardedCache[K, V]) Cost() int64 {
+	var cost int64
+	for i := range sc.shards {
+		s := &sc.shards[i]
+		s.lock.Lock()
+		cost += s.cache.Cost()
+		s.lock.Unlock()
+	}
+	return cost
+}
+
+// Stats returns the sum of the counters of all the shards.
+func (sc *ShardedCache[K, V]) Stats() CacheStats {
+	var stats CacheStats
+	for i := range sc.shards {
+		s := &sc.shards[i]
+		s.lock.Lock()
+		stats.add(s.cache.Stats())
+		s.lock.Unlock()
+	}
+	return stats
+}
+
+func (sc *ShardedCache[K, V]) shard(key K) *cacheShard[K, V] {
+	return &sc.shards[maphash.Comparable(sc.seed, key)%uint64(len(sc.shards))]
+}
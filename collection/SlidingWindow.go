@@ -0,0 +1,273 @@
+package collection
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cnfree/common/maths"
+)
+
+// CountWindow holds the last values of a series, such as the latencies of the last 1000 requests, and
+// computes aggregations over them.
+// A CountWindow is goroutine-safe.
+type CountWindow[T maths.Number] struct {
+	mutex  sync.Mutex
+	values *RingBuffer[T]
+}
+
+// NewCountWindow is the only way to get a new, ready-to-use CountWindow.
+//
+//   size: number of values kept, must be positive
+//
+// Example:
+//
+//   latencies := collection.NewCountWindow[time.Duration](1000)
+//   latencies.Add(time.Since(start))
+//   p99 := time.Duration(latencies.Percentile(99))
+func NewCountWindow[T maths.Number](size int) *CountWindow[T] {
+	return &CountWindow[T]{values: NewRingBuffer[T](size, Overwrite)}
+}
+
+// Add adds a value to the window, dropping the oldest one if the window is full.
+func (w *CountWindow[T]) Add(value T) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.values.Push(value)
+}
+
+// Len returns the number of values in the window.
+func (w *CountWindow[T]) Len() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.values.Len()
+}
+
+// Values returns the values in the window, from the oldest to the newest.
+func (w *CountWindow[T]) Values() []T {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.values.Slice()
+}
+
+// Sum returns the sum of the values in the window.
+func (w *CountWindow[T]) Sum() T {
+	return sum(w.Values())
+}
+
+// Mean returns the arithmetic mean of the values in the window, or NaN if the window is empty.
+func (w *CountWindow[T]) Mean() float64 {
+	return mean(w.Values())
+}
+
+// Min returns the smallest value in the window, or false if the window is empty.
+func (w *CountWindow[T]) Min() (T, bool) {
+	return minimum(w.Values())
+}
+
+// Max returns the largest value in the window, or false if the window is empty.
+func (w *CountWindow[T]) Max() (T, bool) {
+	return maximum(w.Values())
+}
+
+// Percentile returns the p-th percentile of the values in the window, p being between 0 and 100, or NaN
+// if the window is empty or p is NaN. It interpolates linearly between the closest ranks.
+func (w *CountWindow[T]) Percentile(p float64) float64 {
+	return percentiles(w.Values(), p)[0]
+}
+
+// Percentiles returns several percentiles of the values in the window, sorting them only once.
+func (w *CountWindow[T]) Percentiles(ps ...float64) []float64 {
+	return percentiles(w.Values(), ps...)
+}
+
+// Clear removes all the values from the window.
+func (w *CountWindow[T]) Clear() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.values.Clear()
+}
+
+// TimeWindow holds the values of a series added during a recent period, such as the latencies of the
+// requests of the last minute, and computes aggregations over them. The values older than the period are
+// dropped as the window slides.
+// A TimeWindow is goroutine-safe.
+type TimeWindow[T maths.Number] struct {
+	mutex   sync.Mutex
+	period  time.Duration
+	samples []timeSample[T] // in time order, samples[head:] are in the window
+	head    int
+}
+
+type timeSample[T maths.Number] struct {
+	at    time.Time
+	value T
+}
+
+// NewTimeWindow is the only way to get a new, ready-to-use TimeWindow.
+//
+//   period: duration for which the values are kept, must be positive
+//
+// Example:
+//
+//   latencies := collection.NewTimeWindow[float64](time.Minute)
+//   latencies.Add(time.Since(start).Seconds())
+//   fmt.Printf("%.3fs on average over the last minute\n", latencies.Mean())
+func NewTimeWindow[T maths.Number](period time.Duration) *TimeWindow[T] {
+	if period <= 0 {
+		panic("collection: invalid TimeWindow period")
+	}
+	return &TimeWindow[T]{period: period}
+}
+
+// Add adds a value to the window at the current time.
+func (w *TimeWindow[T]) Add(value T) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	now := time.Now()
+	w.expire(now)
+	w.samples = append(w.samples, timeSample[T]{now, value})
+}
+
+// Len returns the number of values in the window.
+func (w *TimeWindow[T]) Len() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.expire(time.Now())
+	return len(w.samples) - w.head
+}
+
+// Values returns the values in the window, from the oldest to the newest.
+func (w *TimeWindow[T]) Values() []T {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.expire(time.Now())
+	values := make([]T, 0, len(w.samples)-w.head)
+	for _, s := range w.samples[w.head:] {
+		values = append(values, s.value)
+	}
+	return values
+}
+
+// Sum returns the sum of the values in the window.
+func (w *TimeWindow[T]) Sum() T {
+	return sum(w.Values())
+}
+
+// Mean returns the arithmetic mean of the values in the window, or NaN if the window is empty.
+func (w *TimeWindow[T]) Mean() float64 {
+	return mean(w.Values())
+}
+
+// Rate returns the sum of the values in the window per second, such as the number of requests per second
+// when adding 1 per request.
+func (w *TimeWindow[T]) Rate() float64 {
+	return float64(w.Sum()) / w.period.Seconds()
+}
+
+// Min returns the smallest value in the window, or false if the window is empty.
+func (w *TimeWindow[T]) Min() (T, bool) {
+	return minimum(w.Values())
+}
+
+// Max returns the largest value in the window, or false if the window is empty.
+func (w *TimeWindow[T]) Max() (T, bool) {
+	return maximum(w.Values())
+}
+
+// Percentile returns the p-th percentile of the values in the window, p being between 0 and 100, or NaN
+// if the window is empty or p is NaN. It interpolates linearly between the closest ranks.
+func (w *TimeWindow[T]) Percentile(p float64) float64 {
+	return percentiles(w.Values(), p)[0]
+}
+
+// Percentiles returns several percentiles of the values in the window, sorting them only once.
+func (w *TimeWindow[T]) Percentiles(ps ...float64) []float64 {
+	return percentiles(w.Values(), ps...)
+}
+
+// Clear removes all the values from the window.
+func (w *TimeWindow[T]) Clear() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.samples, w.head = nil, 0
+}
+
+// expire drops the samples older than the period.
+func (w *TimeWindow[T]) expire(now time.Time) {
+	cutoff := now.Add(-w.period)
+	for w.head < len(w.samples) && !w.samples[w.head].at.After(cutoff) {
+		w.samples[w.head] = timeSample[T]{}
+		w.head++
+	}
+	if w.head == len(w.samples) {
+		w.samples, w.head = w.samples[:0], 0
+	} else if w.head > len(w.samples)/2 {
+		// reclaims the space of the expired samples
+		n := copy(w.samples, w.samples[w.head:])
+		w.samples, w.head = w.samples[:n], 0
+	}
+}
+
+func sum[T maths.Number](values []T) T {
+	var s T
+	for _, v := range values {
+		s += v
+	}
+	return s
+}
+
+func mean[T maths.Number](values []T) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	s := 0.0
+	for _, v := range values {
+		s += float64(v)
+	}
+	return s / float64(len(values))
+}
+
+func minimum[T maths.Number](values []T) (T, bool) {
+	if len(values) == 0 {
+		var zero T
+		return zero, false
+	}
+	return slices.Min(values), true
+}
+
+func maximum[T maths.Number](values []T) (T, bool) {
+	if len(values) == 0 {
+		var zero T
+		return zero, false
+	}
+	return slices.Max(values), true
+}
+
+// percentiles sorts values in place and returns the requested percentiles.
+func percentiles[T maths.Number](values []T, ps ...float64) []float64 {
+	out := make([]float64, len(ps))
+	if len(values) == 0 {
+		for i := range out {
+			out[i] = math.NaN()
+		}
+		return out
+	}
+	slices.Sort(values)
+	for i, p := range ps {
+		if math.IsNaN(p) {
+			out[i] = math.NaN()
+			continue
+		}
+		rank := min(max(p, 0), 100) / 100 * float64(len(values)-1)
+		lower := int(rank)
+		if lower == len(values)-1 {
+			out[i] = float64(values[lower])
+		} else {
+			fraction := rank - float64(lower)
+			out[i] = float64(values[lower])*(1-fraction) + float64(values[lower+1])*fraction
+		}
+	}
+	return out
+}
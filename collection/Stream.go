@@ -0,0 +1,407 @@
+package collection
+
+import "iter"
+
+// Stream is a lazy sequence of elements: the operations are only performed when the elements are consumed
+// by a terminal operation such as ToSlice, ForEach or Reduce.
+// A Stream over a slice or a container can be consumed several times, a Stream over a channel or an
+// Iterator only once. The zero Stream is empty.
+//
+// Example:
+//
+//   // the names of the first 10 active users, in key order
+//   names := collection.Map(
+//       collection.FromSeq2(users.All()).Filter(func(p collection.Pair[int, *User]) bool { return p.Value.Active }),
+//       func(p collection.Pair[int, *User]) string { return p.Value.Name },
+//   ).Take(10).ToSlice()
+type Stream[T any] struct {
+	seq iter.Seq[T]
+}
+
+// Pair is a key and a value, such as an element of a map.
+type Pair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// StreamOf returns a Stream of the given values.
+func StreamOf[T any](values ...T) Stream[T] {
+	return FromSlice(values)
+}
+
+// FromSlice returns a Stream of the elements of s.
+func FromSlice[T any](s []T) Stream[T] {
+	return Stream[T]{func(yield func(T) bool) {
+		for _, v := range s {
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// FromSeq returns a Stream of the elements of a range-over-func iterator.
+func FromSeq[T any](seq iter.Seq[T]) Stream[T] {
+	return Stream[T]{seq}
+}
+
+// FromSeq2 returns a Stream of the pairs of a range-over-func iterator, such as LinkedOrderedMap.All.
+func FromSeq2[K, V any](seq iter.Seq2[K, V]) Stream[Pair[K, V]] {
+	return Stream[Pair[K, V]]{func(yield func(Pair[K, V]) bool) {
+		for k, v := range seq {
+			if !yield(Pair[K, V]{k, v}) {
+				return
+			}
+		}
+	}}
+}
+
+// FromChannel returns a Stream of the values received from c until it's closed.
+func FromChannel[T any](c <-chan T) Stream[T] {
+	return Stream[T]{func(yield func(T) bool) {
+		for v := range c {
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// FromIterator returns a Stream of the values of an Iterator.
+func FromIterator(it Iterator) Stream[interface{}] {
+	return Stream[interface{}]{func(yield func(interface{}) bool) {
+		for ; it.IsValid(); it.Next() {
+			if !yield(it.Value()) {
+				return
+			}
+		}
+	}}
+}
+
+// FromMapIterator returns a Stream of the key-value pairs of a MapIterator.
+func FromMapIterator(it MapIterator) Stream[Pair[interface{}, interface{}]] {
+	return Stream[Pair[interface{}, interface{}]]{func(yield func(Pair[interface{}, interface{}]) bool) {
+		for ; it.IsValid(); it.Next() {
+			if !yield(Pair[interface{}, interface{}]{it.Key(), it.Value()}) {
+				return
+			}
+		}
+	}}
+}
+
+// ContainerIterator is the method set of the iterators of the containers returned by common.Collection,
+// positioned before the first element.
+type ContainerIterator interface {
+	Next() bool
+	Value() interface{}
+}
+
+// FromContainerIterator returns a Stream of the values of a container iterator, such as the one returned by
+// the Iterator method of an arraylist.List or a treeset.Set.
+func FromContainerIterator(it ContainerIterator) Stream[interface{}] {
+	return Stream[interface{}]{func(yield func(interface{}) bool) {
+		for it.Next() {
+			if !yield(it.Value()) {
+				return
+			}
+		}
+	}}
+}
+
+// FromContainer returns a Stream of the values of a container, such as the lists, sets, stacks and maps
+// returned by common.Collection. The values are read when the Stream is consumed.
+func FromContainer(c Container) Stream[interface{}] {
+	return Stream[interface{}]{func(yield func(interface{}) bool) {
+		for _, v := range c.Values() {
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// Seq returns the Stream as a range-over-func iterator. The zero Stream is empty.
+func (s Stream[T]) Seq() iter.Seq[T] {
+	if s.seq == nil {
+		return func(func(T) bool) {}
+	}
+	return s.seq
+}
+
+// Filter returns a Stream of the elements matching pred.
+func (s Stream[T]) Filter(pred func(T) bool) Stream[T] {
+	return Stream[T]{func(yield func(T) bool) {
+		for v := range s.Seq() {
+			if pred(v) && !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// Take returns a Stream of the first n elements.
+func (s Stream[T]) Take(n int) Stream[T] {
+	return Stream[T]{func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v := range s.Seq() {
+			if !yield(v) {
+				return
+			}
+			i++
+			if i >= n {
+				return
+			}
+		}
+	}}
+}
+
+// TakeWhile returns a Stream of the elements up to the first one not matching pred.
+func (s Stream[T]) TakeWhile(pred func(T) bool) Stream[T] {
+	return Stream[T]{func(yield func(T) bool) {
+		for v := range s.Seq() {
+			if !pred(v) || !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// Skip returns a Stream of the elements after the first n ones.
+func (s Stream[T]) Skip(n int) Stream[T] {
+	return Stream[T]{func(yield func(T) bool) {
+		i := 0
+		for v := range s.Seq() {
+			if i < n {
+				i++
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// SkipWhile returns a Stream of the elements from the first one not matching pred.
+func (s Stream[T]) SkipWhile(pred func(T) bool) Stream[T] {
+	return Stream[T]{func(yield func(T) bool) {
+		skipping := true
+		for v := range s.Seq() {
+			if skipping && pred(v) {
+				continue
+			}
+			skipping = false
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// Peek returns a Stream calling fn on each element as it's consumed, typically for debugging.
+func (s Stream[T]) Peek(fn func(T)) Stream[T] {
+	return Stream[T]{func(yield func(T) bool) {
+		for v := range s.Seq() {
+			fn(v)
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// Concat returns a Stream of the elements of s followed by the elements of others.
+func (s Stream[T]) Concat(others ...Stream[T]) Stream[T] {
+	return Stream[T]{func(yield func(T) bool) {
+		for v := range s.Seq() {
+			if !yield(v) {
+				return
+			}
+		}
+		for _, o := range others {
+			for v := range o.Seq() {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}}
+}
+
+// ForEach calls fn on every element.
+func (s Stream[T]) ForEach(fn func(T)) {
+	for v := range s.Seq() {
+		fn(v)
+	}
+}
+
+// ToSlice collects the elements into a slice.
+func (s Stream[T]) ToSlice() []T {
+	var out []T
+	for v := range s.Seq() {
+		out = append(out, v)
+	}
+	return out
+}
+
+// Count returns the number of elements.
+func (s Stream[T]) Count() int {
+	n := 0
+	for range s.Seq() {
+		n++
+	}
+	return n
+}
+
+// First returns the first element and true, or false if the Stream is empty.
+func (s Stream[T]) First() (T, bool) {
+	for v := range s.Seq() {
+		return v, true
+	}
+	var zero T
+	return zero, false
+}
+
+// AnyMatch returns true if at least one element matches pred.
+func (s Stream[T]) AnyMatch(pred func(T) bool) bool {
+	for v := range s.Seq() {
+		if pred(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllMatch returns true if all the elements match pred, or if the Stream is empty.
+func (s Stream[T]) AllMatch(pred func(T) bool) bool {
+	for v := range s.Seq() {
+		if !pred(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Map returns a Stream of the results of fn applied to the elements of s.
+func Map[T, R any](s Stream[T], fn func(T) R) Stream[R] {
+	return Stream[R]{func(yield func(R) bool) {
+		for v := range s.Seq() {
+			if !yield(fn(v)) {
+				return
+			}
+		}
+	}}
+}
+
+// FlatMap returns a Stream of the elements of the Streams returned by fn for each element of s.
+func FlatMap[T, R any](s Stream[T], fn func(T) Stream[R]) Stream[R] {
+	return Stream[R]{func(yield func(R) bool) {
+		for v := range s.Seq() {
+			for r := range fn(v).Seq() {
+				if !yield(r) {
+					return
+				}
+			}
+		}
+	}}
+}
+
+// Zip returns a Stream of the pairs of elements of a and b at the same position.
+// It stops at the end of the shortest one.
+func Zip[A, B any](a Stream[A], b Stream[B]) Stream[Pair[A, B]] {
+	return Stream[Pair[A, B]]{func(yield func(Pair[A, B]) bool) {
+		next, stop := iter.Pull(b.Seq())
+		defer stop()
+		for va := range a.Seq() {
+			vb, ok := next()
+			if !ok || !yield(Pair[A, B]{va, vb}) {
+				return
+			}
+		}
+	}}
+}
+
+// Chunk returns a Stream of slices of size consecutive elements of s. The last one may be shorter.
+func Chunk[T any](s Stream[T], size int) Stream[[]T] {
+	if size <= 0 {
+		size = 1
+	}
+	return Stream[[]T]{func(yield func([]T) bool) {
+		chunk := make([]T, 0, size)
+		for v := range s.Seq() {
+			chunk = append(chunk, v)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, size)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}}
+}
+
+// Distinct returns a Stream of the elements of s without duplicates, keeping the first occurrences.
+func Distinct[T comparable](s Stream[T]) Stream[T] {
+	return DistinctBy(s, func(v T) T { return v })
+}
+
+// DistinctBy returns a Stream of the elements of s whose keys weren't seen yet.
+func DistinctBy[T any, K comparable](s Stream[T], key func(T) K) Stream[T] {
+	return Stream[T]{func(yield func(T) bool) {
+		seen := make(map[K]struct{})
+		for v := range s.Seq() {
+			k := key(v)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// Reduce combines the elements of s into a single value, starting from initial.
+func Reduce[T, R any](s Stream[T], initial R, fn func(R, T) R) R {
+	acc := initial
+	for v := range s.Seq() {
+		acc = fn(acc, v)
+	}
+	return acc
+}
+
+// GroupBy collects the elements of s into a map of slices keyed by the results of key.
+func GroupBy[T any, K comparable](s Stream[T], key func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for v := range s.Seq() {
+		k := key(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
+
+// ToMap collects the elements of s into a map. The last element wins for duplicated keys.
+func ToMap[T any, K comparable, V any](s Stream[T], key func(T) K, value func(T) V) map[K]V {
+	m := make(map[K]V)
+	for v := range s.Seq() {
+		m[key(v)] = value(v)
+	}
+	return m
+}
+
+// ToLinkedOrderedMap collects the elements of s into a LinkedOrderedMap ordered by comparator, keeping the
+// order of the Stream as insertion order. The last element wins for duplicated keys.
+func ToLinkedOrderedMap[T, K, V any](s Stream[T], comparator func(a, b K) int, key func(T) K, value func(T) V) *LinkedOrderedMap[K, V] {
+	m := NewLinkedOrderedMap[K, V](comparator)
+	for v := range s.Seq() {
+		m.Put(key(v), value(v))
+	}
+	return m
+}
@@ -0,0 +1,180 @@
+package collection
+
+import (
+	"sync"
+
+	"github.com/cnfree/common/cmp"
+)
+
+// NewSyncLinkedOrderedMap returns a new, ready-to-use SyncLinkedOrderedMap with a custom comparator.
+func NewSyncLinkedOrderedMap[K, V any](comparator func(a, b K) int) *SyncLinkedOrderedMap[K, V] {
+	return &SyncLinkedOrderedMap[K, V]{m: NewLinkedOrderedMap[K, V](comparator)}
+}
+
+// NewDefaultSyncLinkedOrderedMap returns a new, ready-to-use SyncLinkedOrderedMap whose keys are compared
+// with their natural order.
+func NewDefaultSyncLinkedOrderedMap[K cmp.Ordered, V any]() *SyncLinkedOrderedMap[K, V] {
+	return &SyncLinkedOrderedMap[K, V]{m: NewDefaultLinkedOrderedMap[K, V]()}
+}
+
+// SyncLinkedOrderedMap is a goroutine-safe LinkedOrderedMap guarded by a sync.RWMutex.
+// The iterators of LinkedOrderedMap aren't exposed since they can't be used safely while other goroutines
+// modify the map: use Range, the snapshots or Read instead.
+type SyncLinkedOrderedMap[K, V any] struct {
+	lock sync.RWMutex
+	m    *LinkedOrderedMap[K, V]
+}
+
+// Insert inserts a new element if the map doesn't already contain the key.
+// Return value: true if the insertion took place and false otherwise.
+func (s *SyncLinkedOrderedMap[K, V]) Insert(key K, value V) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.m.Insert(key, value)
+}
+
+// Put inserts a new element or updates the existing element with the new value.
+// Return value: true if the insertion took place and false if the update took place.
+func (s *SyncLinkedOrderedMap[K, V]) Put(key K, value V) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.m.Put(key, value)
+}
+
+// Get returns value of the key and true if the given key is found.
+func (s *SyncLinkedOrderedMap[K, V]) Get(key K) (V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Get(key)
+}
+
+// Erase removes the element with the given key from the map.
+func (s *SyncLinkedOrderedMap[K, V]) Erase(key K) {
+	s.lock.Lock()
+	s.m.Erase(key)
+	s.lock.Unlock()
+}
+
+// ComputeIfAbsent returns the value of the key, or atomically inserts and returns the result of fn if the
+// key is not found.
+func (s *SyncLinkedOrderedMap[K, V]) ComputeIfAbsent(key K, fn func(K) V) V {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if v, ok := s.m.Get(key); ok {
+		return v
+	}
+	v := fn(key)
+	s.m.Put(key, v)
+	return v
+}
+
+// Floor returns the greatest key less than or equal to the given key and its value.
+func (s *SyncLinkedOrderedMap[K, V]) Floor(key K) (K, V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Floor(key)
+}
+
+// Ceiling returns the least key greater than or equal to the given key and its value.
+func (s *SyncLinkedOrderedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Ceiling(key)
+}
+
+// First returns the smallest key of the map and its value, or false if the map is empty.
+func (s *SyncLinkedOrderedMap[K, V]) First() (K, V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.First()
+}
+
+// Last returns the largest key of the map and its value, or false if the map is empty.
+func (s *SyncLinkedOrderedMap[K, V]) Last() (K, V, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Last()
+}
+
+// PollFirst atomically removes the smallest key of the map and returns it with its value.
+func (s *SyncLinkedOrderedMap[K, V]) PollFirst() (K, V, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.m.PollFirst()
+}
+
+// PollLast atomically removes the largest key of the map and returns it with its value.
+func (s *SyncLinkedOrderedMap[K, V]) PollLast() (K, V, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.m.PollLast()
+}
+
+// Size returns the number of elements in the map.
+func (s *SyncLinkedOrderedMap[K, V]) Size() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Size()
+}
+
+// Empty returns true if the map does not contain any element, otherwise it returns false.
+func (s *SyncLinkedOrderedMap[K, V]) Empty() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Empty()
+}
+
+// Clear removes all elements from the map.
+func (s *SyncLinkedOrderedMap[K, V]) Clear() {
+	s.lock.Lock()
+	s.m.Clear()
+	s.lock.Unlock()
+}
+
+// Range calls fn for every element in ascending key order, with the read lock held,
+// until fn returns false. fn mustn't modify the map.
+func (s *SyncLinkedOrderedMap[K, V]) Range(fn func(key K, value V) bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for k, v := range s.m.All() {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+// RangeInsertionOrder is the same as Range, in insertion order.
+func (s *SyncLinkedOrderedMap[K, V]) RangeInsertionOrder(fn func(key K, value V) bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for k, v := range s.m.InsertionOrder() {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+// Keys returns a snapshot of the keys in ascending order.
+func (s *SyncLinkedOrderedMap[K, V]) Keys() []K {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	keys := make([]K, 0, s.m.Size())
+	for k := range s.m.Keys() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Read calls fn with the read lock held. fn mustn't modify m nor keep it.
+func (s *SyncLinkedOrderedMap[K, V]) Read(fn func(m *LinkedOrderedMap[K, V])) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	fn(s.m)
+}
+
+// Write calls fn with the write lock held, for compound updates. fn mustn't keep m.
+func (s *SyncLinkedOrderedMap[K, V]) Write(fn func(m *LinkedOrderedMap[K, V])) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	fn(s.m)
+}
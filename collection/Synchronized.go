@@ -0,0 +1,351 @@
+package collection
+
+import "sync"
+
+// Container is the method set shared by all the containers, such as the ones returned by common.Collection.
+type Container interface {
+	Empty() bool
+	Size() int
+	Clear()
+	Values() []interface{}
+}
+
+// MapContainer is the method set of the key-value containers, such as the hash, tree and bidi maps
+// and the red-black and B trees returned by common.Collection.
+type MapContainer interface {
+	Container
+	Put(key interface{}, value interface{})
+	Get(key interface{}) (value interface{}, found bool)
+	Remove(key interface{})
+	Keys() []interface{}
+}
+
+// SetContainer is the method set of the sets, such as the hash and tree sets returned by common.Collection.
+type SetContainer interface {
+	Container
+	Add(items ...interface{})
+	Remove(items ...interface{})
+	Contains(items ...interface{}) bool
+}
+
+// ListContainer is the method set of the lists, such as the array and linked lists returned by common.Collection.
+type ListContainer interface {
+	Container
+	Get(index int) (interface{}, bool)
+	Remove(index int)
+	Add(values ...interface{})
+	Contains(values ...interface{}) bool
+	Swap(index1, index2 int)
+	Insert(index int, values ...interface{})
+	Set(index int, value interface{})
+}
+
+// NewSynchronizedMap wraps m into a goroutine-safe SynchronizedMap.
+// m mustn't be used directly afterwards.
+//
+// Example:
+//
+//   m := collection.NewSynchronizedMap(treemap.NewWithIntComparator())
+//   m.Put(1, "a")
+//   m.PutIfAbsent(1, "b") // returns "a", false
+func NewSynchronizedMap(m MapContainer) *SynchronizedMap {
+	return &SynchronizedMap{m: m}
+}
+
+// SynchronizedMap is a goroutine-safe wrapper of a MapContainer guarded by a sync.RWMutex.
+// Reads are performed concurrently, writes exclusively.
+type SynchronizedMap struct {
+	lock sync.RWMutex
+	m    MapContainer
+}
+
+// Put inserts or updates an element.
+func (s *SynchronizedMap) Put(key interface{}, value interface{}) {
+	s.lock.Lock()
+	s.m.Put(key, value)
+	s.lock.Unlock()
+}
+
+// Get returns the value of the key and true if it's found.
+func (s *SynchronizedMap) Get(key interface{}) (interface{}, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Get(key)
+}
+
+// Remove removes the element of the key.
+func (s *SynchronizedMap) Remove(key interface{}) {
+	s.lock.Lock()
+	s.m.Remove(key)
+	s.lock.Unlock()
+}
+
+// PutIfAbsent inserts the element only if the key is not found.
+// Return value: the current value of the key and true if the insertion took place.
+func (s *SynchronizedMap) PutIfAbsent(key interface{}, value interface{}) (interface{}, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if current, found := s.m.Get(key); found {
+		return current, false
+	}
+	s.m.Put(key, value)
+	return value, true
+}
+
+// Compute atomically replaces the value of the key by the result of fn, which receives the current value
+// and whether it was found. The element is removed if fn returns false.
+// Return value: the new value and whether the key is present afterwards.
+func (s *SynchronizedMap) Compute(key interface{}, fn func(value interface{}, found bool) (interface{}, bool)) (interface{}, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	value, found := s.m.Get(key)
+	newValue, keep := fn(value, found)
+	if keep {
+		s.m.Put(key, newValue)
+	} else if found {
+		s.m.Remove(key)
+	}
+	return newValue, keep
+}
+
+// Keys returns a snapshot of the keys.
+func (s *SynchronizedMap) Keys() []interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Keys()
+}
+
+// Values returns a snapshot of the values.
+func (s *SynchronizedMap) Values() []interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Values()
+}
+
+// Empty returns true if the map does not contain any element, otherwise it returns false.
+func (s *SynchronizedMap) Empty() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Empty()
+}
+
+// Size returns the number of elements in the map.
+func (s *SynchronizedMap) Size() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.m.Size()
+}
+
+// Clear removes all elements from the map.
+func (s *SynchronizedMap) Clear() {
+	s.lock.Lock()
+	s.m.Clear()
+	s.lock.Unlock()
+}
+
+// Read calls fn with the read lock held, for consistent reads of several elements.
+// fn mustn't modify m nor keep it.
+func (s *SynchronizedMap) Read(fn func(m MapContainer)) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	fn(s.m)
+}
+
+// Write calls fn with the write lock held, for compound updates. fn mustn't keep m.
+func (s *SynchronizedMap) Write(fn func(m MapContainer)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	fn(s.m)
+}
+
+// NewSynchronizedSet wraps set into a goroutine-safe SynchronizedSet.
+// set mustn't be used directly afterwards.
+func NewSynchronizedSet(set SetContainer) *SynchronizedSet {
+	return &SynchronizedSet{s: set}
+}
+
+// SynchronizedSet is a goroutine-safe wrapper of a SetContainer guarded by a sync.RWMutex.
+type SynchronizedSet struct {
+	lock sync.RWMutex
+	s    SetContainer
+}
+
+// Add adds the items to the set.
+func (s *SynchronizedSet) Add(items ...interface{}) {
+	s.lock.Lock()
+	s.s.Add(items...)
+	s.lock.Unlock()
+}
+
+// AddIfAbsent adds the item only if it's not in the set yet.
+// Return value: true if the item was added.
+func (s *SynchronizedSet) AddIfAbsent(item interface{}) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.s.Contains(item) {
+		return false
+	}
+	s.s.Add(item)
+	return true
+}
+
+// Remove removes the items from the set.
+func (s *SynchronizedSet) Remove(items ...interface{}) {
+	s.lock.Lock()
+	s.s.Remove(items...)
+	s.lock.Unlock()
+}
+
+// Contains returns true if all the items are in the set.
+func (s *SynchronizedSet) Contains(items ...interface{}) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.s.Contains(items...)
+}
+
+// Values returns a snapshot of the items.
+func (s *SynchronizedSet) Values() []interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.s.Values()
+}
+
+// Empty returns true if the set does not contain any item, otherwise it returns false.
+func (s *SynchronizedSet) Empty() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.s.Empty()
+}
+
+// Size returns the number of items in the set.
+func (s *SynchronizedSet) Size() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.s.Size()
+}
+
+// Clear removes all items from the set.
+func (s *SynchronizedSet) Clear() {
+	s.lock.Lock()
+	s.s.Clear()
+	s.lock.Unlock()
+}
+
+// Read calls fn with the read lock held. fn mustn't modify set nor keep it.
+func (s *SynchronizedSet) Read(fn func(set SetContainer)) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	fn(s.s)
+}
+
+// Write calls fn with the write lock held, for compound updates. fn mustn't keep set.
+func (s *SynchronizedSet) Write(fn func(set SetContainer)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	fn(s.s)
+}
+
+// NewSynchronizedList wraps list into a goroutine-safe SynchronizedList.
+// list mustn't be used directly afterwards.
+func NewSynchronizedList(list ListContainer) *SynchronizedList {
+	return &SynchronizedList{l: list}
+}
+
+// SynchronizedList is a goroutine-safe wrapper of a ListContainer guarded by a sync.RWMutex.
+// Beware that indexes may be invalidated by other goroutines between two calls, use Write for
+// index based compound updates.
+type SynchronizedList struct {
+	lock sync.RWMutex
+	l    ListContainer
+}
+
+// Get returns the value at index and true, or false if index is out of range.
+func (s *SynchronizedList) Get(index int) (interface{}, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.l.Get(index)
+}
+
+// Remove removes the value at index.
+func (s *SynchronizedList) Remove(index int) {
+	s.lock.Lock()
+	s.l.Remove(index)
+	s.lock.Unlock()
+}
+
+// Add appends the values to the list.
+func (s *SynchronizedList) Add(values ...interface{}) {
+	s.lock.Lock()
+	s.l.Add(values...)
+	s.lock.Unlock()
+}
+
+// Contains returns true if all the values are in the list.
+func (s *SynchronizedList) Contains(values ...interface{}) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.l.Contains(values...)
+}
+
+// Swap swaps the values at the given indexes.
+func (s *SynchronizedList) Swap(index1, index2 int) {
+	s.lock.Lock()
+	s.l.Swap(index1, index2)
+	s.lock.Unlock()
+}
+
+// Insert inserts the values at index, shifting the following values.
+func (s *SynchronizedList) Insert(index int, values ...interface{}) {
+	s.lock.Lock()
+	s.l.Insert(index, values...)
+	s.lock.Unlock()
+}
+
+// Set sets the value at index.
+func (s *SynchronizedList) Set(index int, value interface{}) {
+	s.lock.Lock()
+	s.l.Set(index, value)
+	s.lock.Unlock()
+}
+
+// Values returns a snapshot of the values.
+func (s *SynchronizedList) Values() []interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.l.Values()
+}
+
+// Empty returns true if the list does not contain any value, otherwise it returns false.
+func (s *SynchronizedList) Empty() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.l.Empty()
+}
+
+// Size returns the number of values in the list.
+func (s *SynchronizedList) Size() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.l.Size()
+}
+
+// Clear removes all values from the list.
+func (s *SynchronizedList) Clear() {
+	s.lock.Lock()
+	s.l.Clear()
+	s.lock.Unlock()
+}
+
+// Read calls fn with the read lock held. fn mustn't modify list nor keep it.
+func (s *SynchronizedList) Read(fn func(list ListContainer)) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	fn(s.l)
+}
+
+// Write calls fn with the write lock held, for compound updates. fn mustn't keep list.
+func (s *SynchronizedList) Write(fn func(list ListContainer)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	fn(s.l)
+}
@@ -0,0 +1,173 @@
+package collection
+
+import "iter"
+
+// Table is a two-dimensional map, associating a value to a row key and a column key, such as a matrix of
+// distances between cities. It's stored as a map of rows, so the lookups by row are faster than the ones
+// by column.
+// A Table is not safe for concurrent use.
+type Table[R, C, V any] struct {
+	rows   keyStore[R, keyStore[C, V]]
+	newRow func() keyStore[C, V]
+	size   int
+}
+
+// Cell is an entry of a Table.
+type Cell[R, C, V any] struct {
+	Row    R
+	Column C
+	Value  V
+}
+
+// NewHashTable returns a new, ready-to-use Table whose rows and columns are iterated in no particular order.
+//
+// Example:
+//
+//   distances := collection.NewHashTable[string, string, float64]()
+//   distances.Put("Paris", "Lyon", 465)
+//   d, ok := distances.Get("Paris", "Lyon")
+func NewHashTable[R, C comparable, V any]() *Table[R, C, V] {
+	return &Table[R, C, V]{rows: newHashStore[R, keyStore[C, V]](), newRow: newHashStore[C, V]}
+}
+
+// NewTreeTable returns a new, ready-to-use Table whose rows and columns are iterated in the order of their
+// comparators.
+func NewTreeTable[R, C, V any](rowComparator func(a, b R) int, columnComparator func(a, b C) int) *Table[R, C, V] {
+	return &Table[R, C, V]{
+		rows:   treeStoreFactory[R, keyStore[C, V]](rowComparator)(),
+		newRow: treeStoreFactory[C, V](columnComparator),
+	}
+}
+
+// Put associates the value to the row and column keys, and returns true if the cell was added, false if
+// its value was replaced.
+func (t *Table[R, C, V]) Put(row R, column C, value V) bool {
+	cells, ok := t.rows.get(row)
+	if !ok {
+		cells = t.newRow()
+		t.rows.put(row, cells)
+	}
+	_, found := cells.get(column)
+	cells.put(column, value)
+	if !found {
+		t.size++
+	}
+	return !found
+}
+
+// Get returns the value of the cell and true, or the zero value and false if there's no such cell.
+func (t *Table[R, C, V]) Get(row R, column C) (V, bool) {
+	cells, ok := t.rows.get(row)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return cells.get(column)
+}
+
+// Contains returns true if there's a cell for the row and column keys.
+func (t *Table[R, C, V]) Contains(row R, column C) bool {
+	_, ok := t.Get(row, column)
+	return ok
+}
+
+// ContainsRow returns true if the row has at least one cell.
+func (t *Table[R, C, V]) ContainsRow(row R) bool {
+	_, ok := t.rows.get(row)
+	return ok
+}
+
+// Remove removes the cell and returns true, or returns false if there's no such cell.
+func (t *Table[R, C, V]) Remove(row R, column C) bool {
+	cells, ok := t.rows.get(row)
+	if !ok || !cells.remove(column) {
+		return false
+	}
+	t.size--
+	if cells.len() == 0 {
+		t.rows.remove(row)
+	}
+	return true
+}
+
+// RemoveRow removes all the cells of the row and returns their number.
+func (t *Table[R, C, V]) RemoveRow(row R) int {
+	cells, ok := t.rows.get(row)
+	if !ok {
+		return 0
+	}
+	t.rows.remove(row)
+	t.size -= cells.len()
+	return cells.len()
+}
+
+// Row returns an iterator over the column keys and values of the row.
+func (t *Table[R, C, V]) Row(row R) iter.Seq2[C, V] {
+	return func(yield func(C, V) bool) {
+		if cells, ok := t.rows.get(row); ok {
+			cells.all()(yield)
+		}
+	}
+}
+
+// Column returns an iterator over the row keys and values of the column. It scans all the rows.
+func (t *Table[R, C, V]) Column(column C) iter.Seq2[R, V] {
+	return func(yield func(R, V) bool) {
+		for r, cells := range t.rows.all() {
+			if v, ok := cells.get(column); ok && !yield(r, v) {
+				return
+			}
+		}
+	}
+}
+
+// RowKeys returns an iterator over the keys of the rows having at least one cell.
+func (t *Table[R, C, V]) RowKeys() iter.Seq[R] {
+	return storeKeys(t.rows)
+}
+
+// Len returns the number of cells.
+func (t *Table[R, C, V]) Len() int {
+	return t.size
+}
+
+// RowCount returns the number of rows having at least one cell.
+func (t *Table[R, C, V]) RowCount() int {
+	return t.rows.len()
+}
+
+// Cells returns an iterator over the cells, row by row.
+func (t *Table[R, C, V]) Cells() iter.Seq[Cell[R, C, V]] {
+	return func(yield func(Cell[R, C, V]) bool) {
+		for r, cells := range t.rows.all() {
+			for c, v := range cells.all() {
+				if !yield(Cell[R, C, V]{r, c, v}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// All returns an iterator over the cells, row by row, whose keys are the row and column keys.
+func (t *Table[R, C, V]) All() iter.Seq2[Pair[R, C], V] {
+	return func(yield func(Pair[R, C], V) bool) {
+		for cell := range t.Cells() {
+			if !yield(Pair[R, C]{cell.Row, cell.Column}, cell.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Iterator returns an iterator over the cells in the order of All, whose keys are Pair values of the row
+// and column keys.
+func (t *Table[R, C, V]) Iterator() MapIterator {
+	return newSnapshotIterator(t.All(), t.size)
+}
+
+// Clear removes all the cells.
+func (t *Table[R, C, V]) Clear() {
+	t.rows.clear()
+	t.size = 0
+}
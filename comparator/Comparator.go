@@ -0,0 +1,167 @@
+// Package comparator provides type-safe comparators built with generics, and combinators to derive
+// comparators from others, such as for sorting by several fields.
+//
+// A Comparator[T] can be passed wherever a func(a, b T) int is expected: LinkedOrderedMap, the collection
+// package's trees and caches, slices.SortFunc, and so on. Untyped converts it into a cmp.Comparator for the
+// containers of common.Collection.
+package comparator
+
+import (
+	"time"
+
+	"github.com/cnfree/common/cmp"
+)
+
+// Comparator compares a and b and returns:
+//
+//   0 if they are equal
+//   < 0 if a < b
+//   > 0 if a > b
+type Comparator[T any] func(a, b T) int
+
+// Compare compares two values of an ordered type without reflection. A NaN is less than any other float,
+// -0.0 equals 0.0.
+func Compare[T cmp.Ordered](a, b T) int {
+	return cmp.CompareOrdered(a, b)
+}
+
+// Natural returns the comparator of the natural order of an ordered type.
+func Natural[T cmp.Ordered]() Comparator[T] {
+	return Compare[T]
+}
+
+// Time compares two time instants.
+func Time(a, b time.Time) int {
+	return a.Compare(b)
+}
+
+// Bool orders false before true.
+func Bool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// Reverse returns a comparator of the reverse order of c.
+func Reverse[T any](c Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		return c(b, a)
+	}
+}
+
+// ThenComparing returns a comparator ordering by c, then by each of next in turn for the values c finds equal.
+//
+// Example:
+//
+//   byName := comparator.ThenComparing(
+//       comparator.ByKey(func(p Person) string { return p.LastName }),
+//       comparator.ByKey(func(p Person) string { return p.FirstName }),
+//   )
+func ThenComparing[T any](c Comparator[T], next ...Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		if r := c(a, b); r != 0 {
+			return r
+		}
+		for _, n := range next {
+			if r := n(a, b); r != 0 {
+				return r
+			}
+		}
+		return 0
+	}
+}
+
+// ByKey returns a comparator ordering values by the natural order of a key extracted from them.
+func ByKey[T any, K cmp.Ordered](key func(T) K) Comparator[T] {
+	return func(a, b T) int {
+		return cmp.CompareOrdered(key(a), key(b))
+	}
+}
+
+// ByKeyWith returns a comparator ordering values by a key extracted from them, compared with c.
+func ByKeyWith[T, K any](key func(T) K, c Comparator[K]) Comparator[T] {
+	return func(a, b T) int {
+		return c(key(a), key(b))
+	}
+}
+
+// NullsFirst returns a comparator of pointers ordering nil before any other pointer, and the values of the
+// non-nil pointers with c.
+func NullsFirst[T any](c Comparator[T]) Comparator[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		case b == nil:
+			return 1
+		}
+		return c(*a, *b)
+	}
+}
+
+// NullsLast returns a comparator of pointers ordering nil after any other pointer, and the values of the
+// non-nil pointers with c.
+func NullsLast[T any](c Comparator[T]) Comparator[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return 1
+		case b == nil:
+			return -1
+		}
+		return c(*a, *b)
+	}
+}
+
+// Reverse returns a comparator of the reverse order of c.
+func (c Comparator[T]) Reverse() Comparator[T] {
+	return Reverse(c)
+}
+
+// ThenComparing returns a comparator ordering by c, then by each of next in turn for the values c finds equal.
+func (c Comparator[T]) ThenComparing(next ...Comparator[T]) Comparator[T] {
+	return ThenComparing(c, next...)
+}
+
+// Min returns the smallest of a and b, a if they are equal.
+func (c Comparator[T]) Min(a, b T) T {
+	if c(b, a) < 0 {
+		return b
+	}
+	return a
+}
+
+// Max returns the largest of a and b, a if they are equal.
+func (c Comparator[T]) Max(a, b T) T {
+	if c(b, a) > 0 {
+		return b
+	}
+	return a
+}
+
+// Untyped converts c into a cmp.Comparator, for the containers of common.Collection. The returned
+// comparator panics if it's given values which aren't of type T.
+//
+// Example:
+//
+//   set := common.Collection.NewTreeSetWithComparator(comparator.Untyped(comparator.Time))
+func Untyped[T any](c Comparator[T]) cmp.Comparator {
+	return func(a, b interface{}) int {
+		return c(a.(T), b.(T))
+	}
+}
+
+// Typed converts a cmp.Comparator into a Comparator of values of type T.
+func Typed[T any](c cmp.Comparator) Comparator[T] {
+	return func(a, b T) int {
+		return c(a, b)
+	}
+}
@@ -0,0 +1,69 @@
+package comparator
+
+import (
+	"testing"
+
+	"github.com/cnfree/common/cmp"
+)
+
+// sink keeps the compiler from optimizing the comparisons away.
+var sink int
+
+var (
+	ints     = [2]int{41, 42}
+	int64s   = [2]int64{41, 42}
+	strs     = [2]string{"comparator-a", "comparator-b"}
+	float64s = [2]float64{41.5, 42.5}
+)
+
+// boxed holds the same values as interface{}, boxed once so that the allocations of the boxing aren't
+// measured.
+var boxed = map[string][2]interface{}{
+	"int":     {ints[0], ints[1]},
+	"int64":   {int64s[0], int64s[1]},
+	"string":  {strs[0], strs[1]},
+	"float64": {float64s[0], float64s[1]},
+}
+
+var kinds = []string{"int", "int64", "string", "float64"}
+
+func benchmarkGeneric[T cmp.Ordered](b *testing.B, values [2]T) {
+	for i := 0; i < b.N; i++ {
+		sink += Compare(values[i&1], values[(i+1)&1])
+	}
+}
+
+func BenchmarkCompareGeneric(b *testing.B) {
+	b.Run("int", func(b *testing.B) { benchmarkGeneric(b, ints) })
+	b.Run("int64", func(b *testing.B) { benchmarkGeneric(b, int64s) })
+	b.Run("string", func(b *testing.B) { benchmarkGeneric(b, strs) })
+	b.Run("float64", func(b *testing.B) { benchmarkGeneric(b, float64s) })
+}
+
+func BenchmarkDynamic(b *testing.B) {
+	for _, kind := range kinds {
+		values := boxed[kind]
+		b.Run(kind, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c, err := Dynamic(values[i&1], values[(i+1)&1])
+				if err != nil {
+					b.Fatal(err)
+				}
+				sink += c
+			}
+		})
+	}
+}
+
+// BenchmarkCmpCompareReflect measures cmp.Compare, which has a fast path for int, int64 and string and
+// uses reflection for the other types, such as float64.
+func BenchmarkCmpCompareReflect(b *testing.B) {
+	for _, kind := range kinds {
+		values := boxed[kind]
+		b.Run(kind, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sink += cmp.Compare(values[i&1], values[(i+1)&1])
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package comparator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/cnfree/common/cmp"
+)
+
+// ErrIncomparable is returned by Dynamic for values which can't be compared with each other.
+var ErrIncomparable = errors.New("comparator: incomparable values")
+
+// Dynamic compares two values of unknown types. Unlike cmp.Compare, it doesn't panic: it returns an error
+// wrapping ErrIncomparable if the values can't be compared.
+// Integers, unsigned integers and floats of any size can be compared with each other, strings with strings,
+// byte slices with byte slices, bools with bools, time.Time with time.Time, and cmp.Comparable values with
+// the values their CompareTo method accepts. nil is less than any other value.
+// The common types are compared without reflection.
+func Dynamic(a, b interface{}) (int, error) {
+	switch x := a.(type) {
+	case nil:
+		if b == nil {
+			return 0, nil
+		}
+		return -1, nil
+	case int:
+		if y, ok := b.(int); ok {
+			return cmp.CompareOrdered(x, y), nil
+		}
+	case int64:
+		if y, ok := b.(int64); ok {
+			return cmp.CompareOrdered(x, y), nil
+		}
+	case uint64:
+		if y, ok := b.(uint64); ok {
+			return cmp.CompareOrdered(x, y), nil
+		}
+	case float64:
+		if y, ok := b.(float64); ok {
+			return cmp.CompareOrdered(x, y), nil
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return cmp.CompareOrdered(x, y), nil
+		}
+	case []byte:
+		if y, ok := b.([]byte); ok {
+			return bytes.Compare(x, y), nil
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return Bool(x, y), nil
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), nil
+		}
+	case cmp.Comparable:
+		if y, ok := b.(cmp.Comparable); ok {
+			return x.CompareTo(y)
+		}
+	}
+	if b == nil {
+		return 1, nil
+	}
+	return compareReflect(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// Any compares two values of unknown types like Dynamic, but never fails, so that any values can be mixed
+// in an ordered container. The values are first ordered by their category: nil, numbers, strings, bools,
+// times, then the others, which include the byte slices and the cmp.Comparable values. Within the others,
+// the values are ordered by the names of their types, then like Dynamic if they can be compared.
+func Any(a, b interface{}) int {
+	ra, rb := anyRank(a), anyRank(b)
+	if ra != rb {
+		return cmp.CompareOrdered(ra, rb)
+	}
+	switch ra {
+	case rankNil:
+		return 0
+	case rankOther:
+		if r := cmp.CompareOrdered(fmt.Sprintf("%T", a), fmt.Sprintf("%T", b)); r != 0 {
+			return r
+		}
+	}
+	if r, err := Dynamic(a, b); err == nil {
+		return r
+	}
+	return 0
+}
+
+// the categories of values ordered by Any
+const (
+	rankNil = iota
+	rankNumber
+	rankString
+	rankBool
+	rankTime
+	rankOther
+)
+
+func anyRank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return rankNil
+	case int, int64, uint64, float64:
+		return rankNumber
+	case string:
+		return rankString
+	case bool:
+		return rankBool
+	case time.Time:
+		return rankTime
+	case cmp.Comparable:
+		// compared by CompareTo whatever their kind, which may not agree with the other values of the kind
+		return rankOther
+	}
+	switch k := reflect.TypeOf(v).Kind(); {
+	case isNumber(k):
+		return rankNumber
+	case k == reflect.String:
+		return rankString
+	case k == reflect.Bool:
+		return rankBool
+	}
+	return rankOther
+}
+
+// compareReflect compares the values by kind, such as the values of named numeric types.
+func compareReflect(a, b reflect.Value) (int, error) {
+	switch {
+	case isNumber(a.Kind()) && isNumber(b.Kind()):
+		return compareNumbers(a, b), nil
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return cmp.CompareOrdered(a.String(), b.String()), nil
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		return Bool(a.Bool(), b.Bool()), nil
+	case isBytes(a) && isBytes(b):
+		return bytes.Compare(a.Bytes(), b.Bytes()), nil
+	}
+	return 0, fmt.Errorf("%w: %s and %s", ErrIncomparable, a.Type(), b.Type())
+}
+
+func isNumber(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
+
+func isBytes(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8
+}
+
+// compareNumbers compares numbers of any kinds exactly, even the large integers which floats can't represent.
+func compareNumbers(a, b reflect.Value) int {
+	switch {
+	case a.CanInt() && b.CanInt():
+		return cmp.CompareOrdered(a.Int(), b.Int())
+	case a.CanUint() && b.CanUint():
+		return cmp.CompareOrdered(a.Uint(), b.Uint())
+	case a.CanInt() && b.CanUint():
+		if a.Int() < 0 {
+			return -1
+		}
+		return cmp.CompareOrdered(uint64(a.Int()), b.Uint())
+	case a.CanUint() && b.CanInt():
+		return -compareNumbers(b, a)
+	case a.CanFloat() && b.CanFloat():
+		return cmp.CompareOrdered(a.Float(), b.Float())
+	case a.CanFloat():
+		return compareFloatInteger(a.Float(), b)
+	}
+	return -compareFloatInteger(b.Float(), a)
+}
+
+func compareFloatInteger(f float64, i reflect.Value) int {
+	if math.IsNaN(f) {
+		return -1
+	}
+	var r int
+	if i.CanInt() {
+		r = cmp.CompareOrdered(f, float64(i.Int()))
+	} else {
+		r = cmp.CompareOrdered(f, float64(i.Uint()))
+	}
+	if r != 0 || math.IsInf(f, 0) {
+		return r
+	}
+	// f is integral and within the range of int64 or uint64 here, compares exactly
+	if i.CanInt() {
+		if f >= math.MaxInt64 {
+			return 1
+		}
+		return cmp.CompareOrdered(int64(f), i.Int())
+	}
+	if f < 0 {
+		return -1
+	}
+	if f >= math.MaxUint64 {
+		return 1
+	}
+	return cmp.CompareOrdered(uint64(f), i.Uint())
+}
@@ -0,0 +1,169 @@
+package comparator
+
+import (
+	"fmt"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cnfree/common/cmp"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// NaturalString compares strings in natural order: the runs of digits are compared by their numeric values,
+// so that "file2" comes before "file10". The other characters are compared by their code points.
+// Strings differing only by the leading zeros of their numbers, such as "a01" and "a1", are ordered by
+// the number of leading zeros, so that no distinct strings are equal.
+//
+// Example:
+//
+//   files := collection.NewLinkedOrderedMap[string, os.FileInfo](comparator.NaturalString)
+//   set := common.Collection.NewTreeSetWithComparator(comparator.Untyped(comparator.NaturalString))
+func NaturalString(a, b string) int {
+	return compareNatural(a, b, false)
+}
+
+// NaturalCaseInsensitive compares strings in natural order like NaturalString, ignoring case.
+func NaturalCaseInsensitive(a, b string) int {
+	return compareNatural(a, b, true)
+}
+
+// CaseInsensitive compares strings by the code points of their characters, ignoring case:
+// it returns 0 for "Go" and "GO".
+func CaseInsensitive(a, b string) int {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if r := compareFolded(ra, rb); r != 0 {
+			return r
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return cmp.CompareOrdered(len(a), len(b))
+}
+
+func compareFolded(a, b rune) int {
+	if a == b {
+		return 0
+	}
+	return cmp.CompareOrdered(unicode.ToLower(a), unicode.ToLower(b))
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func compareNatural(a, b string, fold bool) int {
+	zeros := 0 // first difference of leading zeros, the last resort
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			za, zb := i, j
+			for i < len(a) && a[i] == '0' {
+				i++
+			}
+			for j < len(b) && b[j] == '0' {
+				j++
+			}
+			za, zb = i-za, j-zb
+			ea, eb := i, j
+			for ea < len(a) && isDigit(a[ea]) {
+				ea++
+			}
+			for eb < len(b) && isDigit(b[eb]) {
+				eb++
+			}
+			// without leading zeros, the longer number is the larger one
+			if r := cmp.CompareOrdered(ea-i, eb-j); r != 0 {
+				return r
+			}
+			if r := cmp.CompareOrdered(a[i:ea], b[j:eb]); r != 0 {
+				return r
+			}
+			if zeros == 0 {
+				zeros = cmp.CompareOrdered(za, zb)
+			}
+			i, j = ea, eb
+			continue
+		}
+		ra, na := utf8.DecodeRuneInString(a[i:])
+		rb, nb := utf8.DecodeRuneInString(b[j:])
+		r := cmp.CompareOrdered(ra, rb)
+		if fold {
+			r = compareFolded(ra, rb)
+		}
+		if r != 0 {
+			return r
+		}
+		i, j = i+na, j+nb
+	}
+	if r := cmp.CompareOrdered(len(a)-i, len(b)-j); r != 0 {
+		return r
+	}
+	return zeros
+}
+
+// CollationOption changes the rules of a collation comparator.
+type CollationOption int
+
+const (
+	// CollateIgnoreCase compares the strings regardless of case.
+	CollateIgnoreCase CollationOption = iota
+	// CollateIgnoreDiacritics compares the strings regardless of accents and other diacritics.
+	CollateIgnoreDiacritics
+	// CollateIgnoreWidth compares full-width and half-width forms of characters as equal.
+	CollateIgnoreWidth
+	// CollateLoose ignores case, diacritics and width.
+	CollateLoose
+	// CollateNumeric compares the runs of digits by their numeric values.
+	CollateNumeric
+)
+
+var collationOptions = map[CollationOption]collate.Option{
+	CollateIgnoreCase:       collate.IgnoreCase,
+	CollateIgnoreDiacritics: collate.IgnoreDiacritics,
+	CollateIgnoreWidth:      collate.IgnoreWidth,
+	CollateLoose:            collate.Loose,
+	CollateNumeric:          collate.Numeric,
+}
+
+// Collation returns a comparator of strings following the Unicode collation rules of a language, given by
+// its BCP 47 tag such as "fr", "de-DE" or "zh-Hans", so that the accented letters are sorted like users of
+// this language expect.
+// The returned comparator is goroutine-safe.
+//
+// Example:
+//
+//   byName, err := comparator.Collation("fr", comparator.CollateIgnoreCase)
+//   slices.SortFunc(names, byName)
+func Collation(tag string, options ...CollationOption) (Comparator[string], error) {
+	lang, err := language.Parse(tag)
+	if err != nil {
+		return nil, fmt.Errorf("comparator: invalid language tag %q: %w", tag, err)
+	}
+	opts := make([]collate.Option, 0, len(options))
+	for _, o := range options {
+		opt, ok := collationOptions[o]
+		if !ok {
+			return nil, fmt.Errorf("comparator: invalid collation option %d", o)
+		}
+		opts = append(opts, opt)
+	}
+	// a Collator isn't goroutine-safe, every goroutine borrows its own
+	collators := &sync.Pool{New: func() interface{} { return collate.New(lang, opts...) }}
+	return func(a, b string) int {
+		c := collators.Get().(*collate.Collator)
+		defer collators.Put(c)
+		return c.CompareString(a, b)
+	}, nil
+}
+
+// MustCollation is like Collation but panics if the tag or an option is invalid.
+func MustCollation(tag string, options ...CollationOption) Comparator[string] {
+	c, err := Collation(tag, options...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,38 @@
+package job
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Job is a named unit of background work with a JSON payload.
+type Job struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	Attempts  int             `json:"attempts,omitempty"`
+	LastError string          `json:"lastError,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+	RunAt     time.Time       `json:"runAt"`
+	// Backoff is the last delay computed by the retry backoff, kept for the strategies depending on it.
+	Backoff time.Duration `json:"backoff,omitempty"`
+}
+
+// Decode unmarshals the payload of the job into v.
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// Handler processes a job. A non-nil error makes the job retried later, unless it's wrapped by
+// task.Permanent, in which case the job goes straight to the dead-letter list.
+// ctx is canceled when the Queue is stopped and the stop deadline is exceeded.
+type Handler func(ctx context.Context, job *Job) error
+
+func newID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return time.Now().UTC().Format("20060102150405") + "-" + hex.EncodeToString(b[:])
+}
@@ -0,0 +1,173 @@
+package job
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/cnfree/common/pool"
+)
+
+// journal operations
+const (
+	opEnqueue = "enqueue" // a new job, or a job moved back from the dead-letter list
+	opRetry   = "retry"   // a failed job scheduled for another attempt
+	opDone    = "done"    // a job processed successfully
+	opDead    = "dead"    // a job moved to the dead-letter list
+	opDelete  = "delete"  // a dead job removed for good
+)
+
+type record struct {
+	Op  string `json:"op"`
+	Job *Job   `json:"job,omitempty"`
+	ID  string `json:"id,omitempty"`
+}
+
+// journal is an append-only file of JSON records, one per line, from which the state of the queue is
+// rebuilt at startup.
+type journal struct {
+	path    string
+	file    *os.File
+	sync    bool
+	buffers *pool.BufferPool
+}
+
+func openJournal(path string, sync bool) (*journal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &journal{path: path, file: file, sync: sync, buffers: pool.NewBufferPool(16, 512)}, nil
+}
+
+// append writes the records to the end of the journal.
+func (j *journal) append(records ...record) error {
+	buf := j.buffers.Get()
+	defer j.buffers.Put(buf)
+
+	enc := json.NewEncoder(buf)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	if _, err := j.file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if j.sync {
+		return j.file.Sync()
+	}
+	return nil
+}
+
+// rewrite atomically replaces the journal with the given records.
+// The new file is written through the handle kept for the next appends, which follows the file when
+// it's renamed: nothing is left to fail once the journal is replaced.
+func (j *journal) rewrite(records []record) error {
+	tmp := j.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err = enc.Encode(r); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmp, j.path)
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	j.file.Close()
+	j.file = file
+	return nil
+}
+
+func (j *journal) close() error {
+	return j.file.Close()
+}
+
+// replayJournal reads the journal at path and returns the pending and the dead jobs, each sorted by RunAt.
+// A truncated last line, as left by a crash in the middle of a write, is ignored.
+func replayJournal(path string) (pending, dead []*Job, err error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	pendingJobs := make(map[string]*Job)
+	deadJobs := make(map[string]*Job)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	line := 0
+	var corrupted error
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if corrupted != nil {
+			// only the last line may be corrupted
+			return nil, nil, corrupted
+		}
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			corrupted = fmt.Errorf("job: corrupted journal %s at line %d: %v", path, line, err)
+			continue
+		}
+		switch r.Op {
+		case opEnqueue, opRetry:
+			if r.Job != nil {
+				delete(deadJobs, r.Job.ID)
+				pendingJobs[r.Job.ID] = r.Job
+			}
+		case opDead:
+			if r.Job != nil {
+				delete(pendingJobs, r.Job.ID)
+				deadJobs[r.Job.ID] = r.Job
+			}
+		case opDone:
+			delete(pendingJobs, r.ID)
+		case opDelete:
+			delete(deadJobs, r.ID)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return sortedJobs(pendingJobs), sortedJobs(deadJobs), nil
+}
+
+func sortedJobs(jobs map[string]*Job) []*Job {
+	list := make([]*Job, 0, len(jobs))
+	for _, j := range jobs {
+		list = append(list, j)
+	}
+	sort.Slice(list, func(a, b int) bool {
+		if list[a].RunAt.Equal(list[b].RunAt) {
+			return list[a].ID < list[b].ID
+		}
+		return list[a].RunAt.Before(list[b].RunAt)
+	})
+	return list
+}
@@ -0,0 +1,495 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/cnfree/common/collection"
+	"github.com/cnfree/common/pool"
+	"github.com/cnfree/common/task"
+)
+
+var (
+	ErrClosed   = errors.New("job: queue is closed")
+	ErrNotFound = errors.New("job: job not found")
+)
+
+// Options configures a Queue. Zero fields get the default values.
+type Options struct {
+	// Workers is the number of jobs processed concurrently. Default: runtime.NumCPU().
+	Workers int
+	// MaxAttempts is the number of attempts after which a failing job is moved to the dead-letter list. Default: 5.
+	MaxAttempts int
+	// Backoff computes the delay before the next attempt of a failed job. Default: exponential from 1s to 10m.
+	Backoff task.Backoff
+	// PollInterval is how often the jobs waiting for a retry are checked. Default: 1s.
+	PollInterval time.Duration
+	// Sync makes every write to the journal synced to disk before returning.
+	// Slower, but no acknowledged Enqueue is lost on power failure.
+	Sync bool
+	// OnError, if not nil, is called with the errors which can't be returned to a caller,
+	// such as a failure to write to the journal from a worker.
+	OnError func(err error)
+}
+
+// Queue is a durable, goroutine-safe background job queue.
+// Every change is appended to a journal file, so pending and dead jobs survive restarts.
+// Jobs are processed at least once: a job interrupted by a crash is processed again after the restart.
+type Queue struct {
+	options Options
+
+	lock     sync.Mutex // guards the fields below
+	journal  *journal
+	records  int // number of records in the journal
+	handlers map[string]Handler
+	pending  map[string]*Job // ready, delayed and running jobs
+	delayed  []*Job
+	dead     map[string]*Job
+	started  bool
+	stopped  bool
+	closed   bool
+
+	ready      *collection.LockfreeQueue[*Job]
+	goPool     *pool.GoRoutinePool
+	workers    sync.WaitGroup
+	stopCtx    context.Context // canceled by Stop, makes the workers stop taking jobs
+	stopCancel context.CancelFunc
+	ctx        context.Context // canceled when Stop gives up waiting, interrupts the running jobs
+	cancel     context.CancelFunc
+}
+
+// Open is the only way to get a new, ready-to-use Queue.
+// It creates the journal at path if it doesn't exist, or replays it to restore the pending and the
+// dead jobs otherwise. Jobs aren't processed until Start is called.
+//
+// Example:
+//
+//   q, err := job.Open("/var/lib/app/jobs.journal", job.Options{Workers: 4})
+//   if err != nil {
+//       return err
+//   }
+//   defer q.Close()
+//   q.Register("email", func(ctx context.Context, j *job.Job) error {
+//       var mail Mail
+//       if err := j.Decode(&mail); err != nil {
+//           return task.Permanent(err)
+//       }
+//       return send(ctx, mail)
+//   })
+//   q.Start()
+//   q.Enqueue("email", Mail{To: "someone@example.com"})
+func Open(path string, options Options) (*Queue, error) {
+	if options.Workers <= 0 {
+		options.Workers = runtime.NumCPU()
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 5
+	}
+	if options.Backoff == nil {
+		options.Backoff = task.ExponentialBackoff(time.Second, 2, 10*time.Minute)
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = time.Second
+	}
+
+	pending, dead, err := replayJournal(path)
+	if err != nil {
+		return nil, err
+	}
+	journal, err := openJournal(path, options.Sync)
+	if err != nil {
+		return nil, err
+	}
+
+	stopCtx, stopCancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{
+		options:    options,
+		journal:    journal,
+		handlers:   make(map[string]Handler),
+		pending:    make(map[string]*Job),
+		dead:       make(map[string]*Job),
+		ready:      collection.NewLockfreeQueue[*Job](),
+		goPool:     pool.NewGoRoutinePool(options.Workers),
+		stopCtx:    stopCtx,
+		stopCancel: stopCancel,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	now := time.Now()
+	for _, j := range pending {
+		q.pending[j.ID] = j
+		if j.RunAt.After(now) {
+			q.delayed = append(q.delayed, j)
+		} else {
+			q.push(j)
+		}
+	}
+	for _, j := range dead {
+		q.dead[j.ID] = j
+	}
+
+	// drops the history of the finished jobs
+	if err := q.compact(); err != nil {
+		journal.close()
+		return nil, err
+	}
+	return q, nil
+}
+
+// Register sets the handler of the jobs with the given name.
+// Jobs without handler are moved to the dead-letter list when processed.
+func (q *Queue) Register(name string, handler Handler) {
+	q.lock.Lock()
+	q.handlers[name] = handler
+	q.lock.Unlock()
+}
+
+// Enqueue adds a job to the queue. payload is marshaled to JSON.
+// The job is written to the journal once Enqueue returns, and is durable across a crash of the machine
+// only with Options.Sync.
+// Return value: the ID of the new job.
+func (q *Queue) Enqueue(name string, payload interface{}) (string, error) {
+	return q.EnqueueAt(name, payload, time.Now())
+}
+
+// EnqueueAt is the same as Enqueue, except that the job isn't processed before the given time.
+func (q *Queue) EnqueueAt(name string, payload interface{}, at time.Time) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	j := &Job{ID: newID(), Name: name, Payload: data, CreatedAt: now, RunAt: at}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.closed {
+		return "", ErrClosed
+	}
+	if err := q.appendRecords(record{Op: opEnqueue, Job: j}); err != nil {
+		return "", err
+	}
+	q.pending[j.ID] = j
+	if at.After(now) {
+		q.delayed = append(q.delayed, j)
+	} else {
+		q.push(j)
+	}
+	q.maybeCompact()
+	return j.ID, nil
+}
+
+// Start starts the workers and the scheduler of the retries. A stopped Queue can't be started again.
+func (q *Queue) Start() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.started || q.closed {
+		return
+	}
+	q.started = true
+
+	q.workers.Add(q.options.Workers + 1)
+	for i := 0; i < q.options.Workers; i++ {
+		q.goPool.Run(q.worker)
+	}
+	go q.scheduler()
+}
+
+// Stop stops taking new jobs and waits for the running ones to finish.
+// If ctx is done first, the contexts of the running jobs are canceled, and Stop still waits for
+// them to return before returning ctx's error. The interrupted jobs are processed again at the next Open.
+func (q *Queue) Stop(ctx context.Context) error {
+	q.lock.Lock()
+	if !q.started || q.stopped {
+		q.lock.Unlock()
+		return nil
+	}
+	q.stopped = true
+	q.stopCancel()
+	q.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Close stops the Queue, waiting for the running jobs to finish, and closes the journal.
+func (q *Queue) Close() error {
+	q.Stop(context.Background())
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+	q.cancel()
+	return q.journal.close()
+}
+
+// Len returns the number of pending jobs, including the running ones and the ones waiting for a retry.
+func (q *Queue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.pending)
+}
+
+// DeadLetters returns a copy of the jobs which exhausted their attempts or failed permanently,
+// sorted by the time of their last attempt.
+func (q *Queue) DeadLetters() []Job {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	list := make([]Job, 0, len(q.dead))
+	for _, j := range sortedJobs(q.dead) {
+		list = append(list, *j)
+	}
+	return list
+}
+
+// Requeue moves a dead job back to the queue with its attempts reset.
+func (q *Queue) Requeue(id string) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	j, ok := q.dead[id]
+	if !ok {
+		return ErrNotFound
+	}
+	return q.requeue(j)
+}
+
+// RequeueAll moves all the dead jobs back to the queue with their attempts reset.
+// Return value: the number of requeued jobs.
+func (q *Queue) RequeueAll() (int, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.closed {
+		return 0, ErrClosed
+	}
+	n := 0
+	for _, j := range sortedJobs(q.dead) {
+		if err := q.requeue(j); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// DeleteDead removes a job from the dead-letter list for good.
+func (q *Queue) DeleteDead(id string) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	if _, ok := q.dead[id]; !ok {
+		return ErrNotFound
+	}
+	if err := q.appendRecords(record{Op: opDelete, ID: id}); err != nil {
+		return err
+	}
+	delete(q.dead, id)
+	q.maybeCompact()
+	return nil
+}
+
+// Compact rewrites the journal with only the pending and the dead jobs.
+// It's done automatically at Open and when the journal grows much bigger than the live jobs.
+func (q *Queue) Compact() error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	return q.compact()
+}
+
+// requeue must be called with the lock held.
+func (q *Queue) requeue(j *Job) error {
+	requeued := *j
+	requeued.Attempts = 0
+	requeued.Backoff = 0
+	requeued.LastError = ""
+	requeued.RunAt = time.Now()
+	if err := q.appendRecords(record{Op: opEnqueue, Job: &requeued}); err != nil {
+		return err
+	}
+	delete(q.dead, j.ID)
+	q.pending[j.ID] = &requeued
+	q.push(&requeued)
+	q.maybeCompact()
+	return nil
+}
+
+// compact must be called with the lock held.
+func (q *Queue) compact() error {
+	records := make([]record, 0, len(q.pending)+len(q.dead))
+	for _, j := range sortedJobs(q.pending) {
+		copied := *j
+		records = append(records, record{Op: opEnqueue, Job: &copied})
+	}
+	for _, j := range sortedJobs(q.dead) {
+		records = append(records, record{Op: opDead, Job: j})
+	}
+	if err := q.journal.rewrite(records); err != nil {
+		return err
+	}
+	q.records = len(records)
+	return nil
+}
+
+// appendRecords must be called with the lock held.
+func (q *Queue) appendRecords(records ...record) error {
+	if err := q.journal.append(records...); err != nil {
+		return err
+	}
+	q.records += len(records)
+	return nil
+}
+
+// maybeCompact compacts the journal if it grew much bigger than the live jobs. It must be called with
+// the lock held, once the pending and dead jobs are up to date, since they are what the journal is
+// rewritten from. A failure doesn't lose anything, the journal is only left as it is, so it's reported
+// to OnError only.
+func (q *Queue) maybeCompact() {
+	if q.records > 1024 && q.records > 4*(len(q.pending)+len(q.dead)) {
+		if err := q.compact(); err != nil {
+			q.onError(err)
+		}
+	}
+}
+
+// push makes a job available to the workers.
+func (q *Queue) push(j *Job) {
+	q.ready.Push(j)
+}
+
+func (q *Queue) worker() {
+	defer q.workers.Done()
+	for q.stopCtx.Err() == nil {
+		j, err := q.ready.PopWait(q.stopCtx)
+		if err != nil {
+			return
+		}
+		q.process(j)
+	}
+}
+
+// scheduler moves the jobs waiting for a retry to the ready queue once they are due.
+func (q *Queue) scheduler() {
+	defer q.workers.Done()
+	ticker := time.NewTicker(q.options.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.stopCtx.Done():
+			return
+		case now := <-ticker.C:
+			q.lock.Lock()
+			waiting := q.delayed[:0]
+			for _, j := range q.delayed {
+				if j.RunAt.After(now) {
+					waiting = append(waiting, j)
+				} else {
+					q.push(j)
+				}
+			}
+			for i := len(waiting); i < len(q.delayed); i++ {
+				q.delayed[i] = nil
+			}
+			q.delayed = waiting
+			q.lock.Unlock()
+		}
+	}
+}
+
+func (q *Queue) process(j *Job) {
+	q.lock.Lock()
+	handler := q.handlers[j.Name]
+	q.lock.Unlock()
+
+	var err error
+	if handler == nil {
+		err = task.Permanent(fmt.Errorf("job: no handler registered for %q", j.Name))
+	} else {
+		err = q.run(handler, j)
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.closed {
+		return
+	}
+
+	if err == nil {
+		if err := q.appendRecords(record{Op: opDone, ID: j.ID}); err != nil {
+			q.onError(err)
+		}
+		delete(q.pending, j.ID)
+		q.maybeCompact()
+		return
+	}
+	if q.ctx.Err() != nil {
+		// interrupted by Stop, it stays pending and will be processed again at the next Open
+		return
+	}
+
+	failed := *j
+	failed.Attempts++
+	failed.LastError = err.Error()
+	if task.IsPermanent(err) || failed.Attempts >= q.options.MaxAttempts {
+		failed.RunAt = time.Now()
+		if err := q.appendRecords(record{Op: opDead, Job: &failed}); err != nil {
+			q.onError(err)
+		}
+		delete(q.pending, j.ID)
+		q.dead[j.ID] = &failed
+		q.maybeCompact()
+		return
+	}
+
+	failed.Backoff = q.options.Backoff.Delay(failed.Attempts, j.Backoff)
+	failed.RunAt = time.Now().Add(failed.Backoff)
+	if err := q.appendRecords(record{Op: opRetry, Job: &failed}); err != nil {
+		q.onError(err)
+	}
+	q.pending[j.ID] = &failed
+	q.delayed = append(q.delayed, &failed)
+	q.maybeCompact()
+}
+
+// run calls the handler, turning a panic into an error.
+func (q *Queue) run(handler Handler, j *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job: handler of %q panicked: %v", j.Name, r)
+		}
+	}()
+	copied := *j
+	return handler(q.ctx, &copied)
+}
+
+func (q *Queue) onError(err error) {
+	if q.options.OnError != nil {
+		q.options.OnError(err)
+	}
+}
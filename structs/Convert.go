@@ -0,0 +1,731 @@
+package structs
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// TimeLayouts are the layouts tried in order when a string is converted
+	// to a time.Time. A field can use its own layout with a "layout" tag:
+	//
+	//   // Field is parsed from and formatted to a date only.
+	//   Birthday time.Time `layout:"2006-01-02"`
+	TimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02", time.RFC1123Z, time.RFC1123}
+
+	// TimeFormat is the layout used when a time.Time is converted to a string.
+	TimeFormat = time.RFC3339Nano
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// maxDepth bounds the recursion of a conversion, which would never end on
+// cyclic data.
+const maxDepth = 1000
+
+// structField is an exported field of a struct type, embedded and flattened
+// struct fields being expanded in place.
+type structField struct {
+	name   string // Go name of the field
+	key    string // tag name, or Go name if the tag has no name
+	index  []int
+	opts   tagOptions
+	layout string
+	field  reflect.StructField
+}
+
+type fieldCacheKey struct {
+	t       reflect.Type
+	tagName string
+}
+
+var fieldCache sync.Map // fieldCacheKey -> []structField
+
+// cachedFields returns the fields of the struct type t. A field hidden by
+// another field of the same key declared at a shallower depth is left out,
+// like the Go selector rules do.
+func cachedFields(t reflect.Type, tagName string) []structField {
+	key := fieldCacheKey{t, tagName}
+	if f, ok := fieldCache.Load(key); ok {
+		return f.([]structField)
+	}
+
+	all := flatFields(t, tagName)
+	depth := make(map[string]int, len(all))
+	for _, f := range all {
+		if d, ok := depth[f.key]; !ok || len(f.index) < d {
+			depth[f.key] = len(f.index)
+		}
+	}
+	fields := make([]structField, 0, len(all))
+	for _, f := range all {
+		if len(f.index) == depth[f.key] {
+			fields = append(fields, f)
+		}
+	}
+
+	f, _ := fieldCache.LoadOrStore(key, fields)
+	return f.([]structField)
+}
+
+func flatFields(t reflect.Type, tagName string) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(tagName)
+		if tag == "-" {
+			continue
+		}
+		name, opts := parseTag(tag)
+
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType && !opts.Has("omitnested") &&
+			((field.Anonymous && name == "") || opts.Has("flatten")) {
+			for _, sub := range flatFields(ft, tagName) {
+				sub.index = append([]int{i}, sub.index...)
+				fields = append(fields, sub)
+			}
+			continue
+		}
+
+		// we can't access the value of unexported fields
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, structField{
+			name:   field.Name,
+			key:    name,
+			index:  []int{i},
+			opts:   opts,
+			layout: field.Tag.Get("layout"),
+			field:  field,
+		})
+	}
+	return fields
+}
+
+// fieldByIndex is the same as reflect.Value.FieldByIndex, except that it
+// allocates the nil embedded pointers on the way if alloc is true. Otherwise,
+// or if such a pointer can't be set, the boolean is false.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// fieldPath locates a value being converted. full is used in errors, general
+// has no slice indices or map keys and is used to report each unmapped field
+// only once.
+type fieldPath struct {
+	full    string
+	general string
+}
+
+func (p fieldPath) field(name string) fieldPath {
+	if p.full == "" {
+		return fieldPath{name, name}
+	}
+	return fieldPath{p.full + "." + name, p.general + "." + name}
+}
+
+func (p fieldPath) index(key interface{}) fieldPath {
+	return fieldPath{fmt.Sprintf("%s[%v]", p.full, key), p.general + "[]"}
+}
+
+// converter assigns values to values of other types. The errors are
+// collected rather than returned, so that a conversion reports all the
+// fields which failed and not only the first one.
+type converter struct {
+	tagName string
+	// weak enables the weakly typed conversions: booleans from and to
+	// numbers, empty strings as zero values and single values as slices.
+	weak bool
+	// merge decodes into the structs already pointed to by the destination
+	// pointers rather than into new ones.
+	merge bool
+	// errorUnused fails on the map keys matching no struct field.
+	errorUnused bool
+	report      *MapReport
+	seen        map[string]bool
+	errs        Errors
+	depth       int
+}
+
+func (c *converter) fail(path fieldPath, format string, args ...interface{}) {
+	c.errs = append(c.errs, &FieldError{Field: path.full, Err: fmt.Errorf(format, args...)})
+}
+
+// unmapped reports a field without counterpart, of the source struct if
+// source is true or of the destination struct otherwise.
+func (c *converter) unmapped(source bool, path fieldPath) {
+	if c.report == nil {
+		return
+	}
+	key := fmt.Sprint(source, path.general)
+	if c.seen[key] {
+		return
+	}
+	if c.seen == nil {
+		c.seen = make(map[string]bool)
+	}
+	c.seen[key] = true
+	if source {
+		c.report.UnmappedSource = append(c.report.UnmappedSource, path.general)
+	} else {
+		c.report.UnmappedTarget = append(c.report.UnmappedTarget, path.general)
+	}
+}
+
+// assign converts src and stores it into dst, which must be settable. An
+// invalid src, a nil pointer or a nil interface sets dst to its zero value.
+// layout is the time layout of the field being converted, if any.
+func (c *converter) assign(dst, src reflect.Value, path fieldPath, layout string) {
+	c.depth++
+	defer func() { c.depth-- }()
+	if c.depth > maxDepth {
+		c.fail(path, "maximum depth %d exceeded, the value may be cyclic", maxDepth)
+		return
+	}
+
+	for src.Kind() == reflect.Interface && !src.IsNil() {
+		src = src.Elem()
+	}
+	if !src.IsValid() || ((src.Kind() == reflect.Ptr || src.Kind() == reflect.Interface) && src.IsNil()) {
+		dst.Set(reflect.Zero(dst.Type()))
+		return
+	}
+
+	switch {
+	case dst.Kind() == reflect.Interface:
+		if !src.Type().Implements(dst.Type()) {
+			c.fail(path, "%s does not implement %s", src.Type(), dst.Type())
+			return
+		}
+		dst.Set(src)
+		return
+	case dst.Kind() == reflect.Ptr:
+		if c.merge && !dst.IsNil() {
+			c.assign(dst.Elem(), src, path, layout)
+			return
+		}
+		elem := reflect.New(dst.Type().Elem())
+		c.assign(elem.Elem(), src, path, layout)
+		dst.Set(elem)
+		return
+	case src.Kind() == reflect.Ptr:
+		c.assign(dst, src.Elem(), path, layout)
+		return
+	}
+
+	if c.assignSpecial(dst, src, path, layout) {
+		return
+	}
+
+	if src.Type() == dst.Type() {
+		switch src.Kind() {
+		case reflect.Slice, reflect.Map, reflect.Array:
+			// copied element by element below, so that dst doesn't share
+			// its storage with src
+		default:
+			dst.Set(src)
+			return
+		}
+	}
+
+	var err error
+	switch dst.Kind() {
+	case reflect.Bool:
+		err = c.assignBool(dst, src)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		err = c.assignInt(dst, src)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		err = c.assignUint(dst, src)
+	case reflect.Float32, reflect.Float64:
+		err = c.assignFloat(dst, src)
+	case reflect.String:
+		err = c.assignString(dst, src)
+	case reflect.Struct:
+		switch src.Kind() {
+		case reflect.Struct:
+			c.structToStruct(dst, src, path)
+		case reflect.Map:
+			c.mapToStruct(dst, src, path)
+		default:
+			err = errCannotConvert(src, dst)
+		}
+	case reflect.Slice:
+		c.assignSlice(dst, src, path, layout)
+	case reflect.Array:
+		c.assignArray(dst, src, path, layout)
+	case reflect.Map:
+		c.assignMap(dst, src, path, layout)
+	default:
+		if !src.Type().AssignableTo(dst.Type()) {
+			err = errCannotConvert(src, dst)
+			break
+		}
+		dst.Set(src)
+	}
+	if err != nil {
+		c.errs = append(c.errs, &FieldError{Field: path.full, Err: err})
+	}
+}
+
+// assignSpecial handles times, durations and the types implementing
+// encoding.TextMarshaler or encoding.TextUnmarshaler. It returns false if
+// none of them is involved.
+func (c *converter) assignSpecial(dst, src reflect.Value, path fieldPath, layout string) bool {
+	switch {
+	case dst.Type() == timeType:
+		switch {
+		case src.Type() == timeType:
+			dst.Set(src)
+		case src.Kind() == reflect.String:
+			if src.Len() == 0 && c.weak {
+				dst.Set(reflect.Zero(timeType))
+				break
+			}
+			t, err := parseTime(src.String(), layout)
+			if err != nil {
+				c.errs = append(c.errs, &FieldError{Field: path.full, Err: err})
+				break
+			}
+			dst.Set(reflect.ValueOf(t))
+		case isInt(src.Kind()):
+			dst.Set(reflect.ValueOf(time.Unix(src.Int(), 0)))
+		default:
+			c.fail(path, "cannot convert %s into time.Time", src.Type())
+		}
+		return true
+	case src.Type() == timeType:
+		t := src.Interface().(time.Time)
+		switch {
+		case dst.Kind() == reflect.String:
+			if layout == "" {
+				layout = TimeFormat
+			}
+			dst.SetString(t.Format(layout))
+		case isInt(dst.Kind()):
+			dst.SetInt(t.Unix())
+		default:
+			return false
+		}
+		return true
+	case dst.Type() == durationType && src.Kind() == reflect.String:
+		if src.Len() == 0 && c.weak {
+			dst.SetInt(0)
+			return true
+		}
+		d, err := time.ParseDuration(src.String())
+		if err != nil {
+			c.errs = append(c.errs, &FieldError{Field: path.full, Err: err})
+			return true
+		}
+		dst.SetInt(int64(d))
+		return true
+	case src.Kind() == reflect.String && src.Type() != dst.Type() && dst.CanAddr():
+		u, ok := dst.Addr().Interface().(encoding.TextUnmarshaler)
+		if !ok {
+			return false
+		}
+		if err := u.UnmarshalText([]byte(src.String())); err != nil {
+			c.errs = append(c.errs, &FieldError{Field: path.full, Err: err})
+		}
+		return true
+	case dst.Kind() == reflect.String && src.Kind() != reflect.String:
+		m, ok := src.Interface().(encoding.TextMarshaler)
+		if !ok {
+			return false
+		}
+		text, err := m.MarshalText()
+		if err != nil {
+			c.errs = append(c.errs, &FieldError{Field: path.full, Err: err})
+			return true
+		}
+		dst.SetString(string(text))
+		return true
+	}
+	return false
+}
+
+func parseTime(s, layout string) (time.Time, error) {
+	if layout != "" {
+		return time.Parse(layout, s)
+	}
+	for _, l := range TimeLayouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a time", s)
+}
+
+func (c *converter) assignBool(dst, src reflect.Value) error {
+	switch {
+	case src.Kind() == reflect.Bool:
+		dst.SetBool(src.Bool())
+	case src.Kind() == reflect.String:
+		if src.Len() == 0 && c.weak {
+			dst.SetBool(false)
+			break
+		}
+		b, err := strconv.ParseBool(src.String())
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+	case c.weak && isInt(src.Kind()):
+		dst.SetBool(src.Int() != 0)
+	case c.weak && isUint(src.Kind()):
+		dst.SetBool(src.Uint() != 0)
+	case c.weak && isFloat(src.Kind()):
+		dst.SetBool(src.Float() != 0)
+	default:
+		return errCannotConvert(src, dst)
+	}
+	return nil
+}
+
+func (c *converter) assignInt(dst, src reflect.Value) error {
+	var n int64
+	switch {
+	case isInt(src.Kind()):
+		n = src.Int()
+	case isUint(src.Kind()):
+		u := src.Uint()
+		if u > math.MaxInt64 {
+			return fmt.Errorf("value %d overflows %s", u, dst.Type())
+		}
+		n = int64(u)
+	case isFloat(src.Kind()):
+		f := src.Float()
+		if f != math.Trunc(f) && !c.weak {
+			return fmt.Errorf("value %v is not an integer", f)
+		}
+		if f < math.MinInt64 || f >= math.MaxInt64 || math.IsNaN(f) {
+			return fmt.Errorf("value %v overflows %s", f, dst.Type())
+		}
+		n = int64(f)
+	case src.Kind() == reflect.String:
+		s := src.String()
+		if s == "" && c.weak {
+			break
+		}
+		var err error
+		if n, err = strconv.ParseInt(s, 10, 64); err != nil {
+			if !c.weak {
+				return err
+			}
+			f, ferr := strconv.ParseFloat(s, 64)
+			if ferr != nil || f < math.MinInt64 || f >= math.MaxInt64 {
+				return err
+			}
+			n = int64(f)
+		}
+	case c.weak && src.Kind() == reflect.Bool:
+		if src.Bool() {
+			n = 1
+		}
+	default:
+		return errCannotConvert(src, dst)
+	}
+	if dst.OverflowInt(n) {
+		return fmt.Errorf("value %d overflows %s", n, dst.Type())
+	}
+	dst.SetInt(n)
+	return nil
+}
+
+func (c *converter) assignUint(dst, src reflect.Value) error {
+	var n uint64
+	switch {
+	case isInt(src.Kind()):
+		i := src.Int()
+		if i < 0 {
+			return fmt.Errorf("value %d overflows %s", i, dst.Type())
+		}
+		n = uint64(i)
+	case isUint(src.Kind()):
+		n = src.Uint()
+	case isFloat(src.Kind()):
+		f := src.Float()
+		if f != math.Trunc(f) && !c.weak {
+			return fmt.Errorf("value %v is not an integer", f)
+		}
+		if f < 0 || f >= math.MaxUint64 || math.IsNaN(f) {
+			return fmt.Errorf("value %v overflows %s", f, dst.Type())
+		}
+		n = uint64(f)
+	case src.Kind() == reflect.String:
+		s := src.String()
+		if s == "" && c.weak {
+			break
+		}
+		var err error
+		if n, err = strconv.ParseUint(s, 10, 64); err != nil {
+			if !c.weak {
+				return err
+			}
+			f, ferr := strconv.ParseFloat(s, 64)
+			if ferr != nil || f < 0 || f >= math.MaxUint64 {
+				return err
+			}
+			n = uint64(f)
+		}
+	case c.weak && src.Kind() == reflect.Bool:
+		if src.Bool() {
+			n = 1
+		}
+	default:
+		return errCannotConvert(src, dst)
+	}
+	if dst.OverflowUint(n) {
+		return fmt.Errorf("value %d overflows %s", n, dst.Type())
+	}
+	dst.SetUint(n)
+	return nil
+}
+
+func (c *converter) assignFloat(dst, src reflect.Value) error {
+	var f float64
+	switch {
+	case isInt(src.Kind()):
+		f = float64(src.Int())
+	case isUint(src.Kind()):
+		f = float64(src.Uint())
+	case isFloat(src.Kind()):
+		f = src.Float()
+	case src.Kind() == reflect.String:
+		s := src.String()
+		if s == "" && c.weak {
+			break
+		}
+		var err error
+		if f, err = strconv.ParseFloat(s, dst.Type().Bits()); err != nil {
+			return err
+		}
+	case c.weak && src.Kind() == reflect.Bool:
+		if src.Bool() {
+			f = 1
+		}
+	default:
+		return errCannotConvert(src, dst)
+	}
+	if dst.OverflowFloat(f) {
+		return fmt.Errorf("value %v overflows %s", f, dst.Type())
+	}
+	dst.SetFloat(f)
+	return nil
+}
+
+func (c *converter) assignString(dst, src reflect.Value) error {
+	switch {
+	case src.Kind() == reflect.String:
+		dst.SetString(src.String())
+	case isInt(src.Kind()):
+		dst.SetString(strconv.FormatInt(src.Int(), 10))
+	case isUint(src.Kind()):
+		dst.SetString(strconv.FormatUint(src.Uint(), 10))
+	case isFloat(src.Kind()):
+		dst.SetString(strconv.FormatFloat(src.Float(), 'g', -1, src.Type().Bits()))
+	case src.Kind() == reflect.Bool:
+		dst.SetString(strconv.FormatBool(src.Bool()))
+	case src.Kind() == reflect.Slice && src.Type().Elem().Kind() == reflect.Uint8:
+		dst.SetString(string(src.Bytes()))
+	default:
+		if s, ok := src.Interface().(fmt.Stringer); ok {
+			dst.SetString(s.String())
+			return nil
+		}
+		return errCannotConvert(src, dst)
+	}
+	return nil
+}
+
+func (c *converter) assignSlice(dst, src reflect.Value, path fieldPath, layout string) {
+	switch {
+	case src.Kind() == reflect.Slice || src.Kind() == reflect.Array:
+		if src.Kind() == reflect.Slice && src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+		s := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			c.assign(s.Index(i), src.Index(i), path.index(i), layout)
+		}
+		dst.Set(s)
+	case src.Kind() == reflect.String && dst.Type().Elem().Kind() == reflect.Uint8:
+		dst.SetBytes([]byte(src.String()))
+	case c.weak:
+		s := reflect.MakeSlice(dst.Type(), 1, 1)
+		c.assign(s.Index(0), src, path.index(0), layout)
+		dst.Set(s)
+	default:
+		c.errs = append(c.errs, &FieldError{Field: path.full, Err: errCannotConvert(src, dst)})
+	}
+}
+
+func (c *converter) assignArray(dst, src reflect.Value, path fieldPath, layout string) {
+	if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+		c.errs = append(c.errs, &FieldError{Field: path.full, Err: errCannotConvert(src, dst)})
+		return
+	}
+	if src.Len() > dst.Len() {
+		c.fail(path, "%d elements don't fit into %s", src.Len(), dst.Type())
+		return
+	}
+	for i := 0; i < dst.Len(); i++ {
+		if i < src.Len() {
+			c.assign(dst.Index(i), src.Index(i), path.index(i), layout)
+		} else {
+			dst.Index(i).Set(reflect.Zero(dst.Type().Elem()))
+		}
+	}
+}
+
+func (c *converter) assignMap(dst, src reflect.Value, path fieldPath, layout string) {
+	switch src.Kind() {
+	case reflect.Map:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+		m := reflect.MakeMapWithSize(dst.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			p := path.index(iter.Key())
+			k := reflect.New(dst.Type().Key()).Elem()
+			n := len(c.errs)
+			c.assign(k, iter.Key(), p, "")
+			v := reflect.New(dst.Type().Elem()).Elem()
+			c.assign(v, iter.Value(), p, layout)
+			if len(c.errs) == n {
+				m.SetMapIndex(k, v)
+			}
+		}
+		dst.Set(m)
+	case reflect.Struct:
+		if dst.Type().Key().Kind() != reflect.String {
+			c.errs = append(c.errs, &FieldError{Field: path.full, Err: errCannotConvert(src, dst)})
+			return
+		}
+		c.structToMap(dst, src, path)
+	default:
+		c.errs = append(c.errs, &FieldError{Field: path.full, Err: errCannotConvert(src, dst)})
+	}
+}
+
+// structToStruct assigns the fields of src to the fields of dst having the
+// same key. A destination field is matched with the source field of the same
+// key, then of the same Go name, then of the same key regardless of case.
+func (c *converter) structToStruct(dst, src reflect.Value, path fieldPath) {
+	srcFields := cachedFields(src.Type(), c.tagName)
+	byKey := make(map[string]int, len(srcFields))
+	byName := make(map[string]int, len(srcFields))
+	byFold := make(map[string]int, len(srcFields))
+	for i := len(srcFields) - 1; i >= 0; i-- {
+		byKey[srcFields[i].key] = i
+		byName[srcFields[i].name] = i
+		byFold[strings.ToLower(srcFields[i].key)] = i
+	}
+
+	used := make([]bool, len(srcFields))
+	for _, df := range cachedFields(dst.Type(), c.tagName) {
+		p := path.field(df.name)
+		i, ok := byKey[df.key]
+		if !ok {
+			i, ok = byName[df.name]
+		}
+		if !ok {
+			i, ok = byFold[strings.ToLower(df.key)]
+		}
+		if !ok || used[i] {
+			c.unmapped(false, p)
+			continue
+		}
+		used[i] = true
+
+		sf := srcFields[i]
+		sv, ok := fieldByIndex(src, sf.index, false)
+		if !ok || (sf.opts.Has("omitempty") && sv.IsZero()) {
+			continue
+		}
+		dv, ok := fieldByIndex(dst, df.index, true)
+		if !ok || !dv.CanSet() {
+			c.errs = append(c.errs, &FieldError{Field: p.full, Err: errNotSettable})
+			continue
+		}
+		layout := df.layout
+		if layout == "" {
+			layout = sf.layout
+		}
+		c.assign(dv, sv, p, layout)
+	}
+
+	for i, sf := range srcFields {
+		if !used[i] {
+			c.unmapped(true, path.field(sf.name))
+		}
+	}
+}
+
+// structToMap stores the fields of src into dst, keyed by their key.
+func (c *converter) structToMap(dst, src reflect.Value, path fieldPath) {
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMap(dst.Type()))
+	}
+	for _, sf := range cachedFields(src.Type(), c.tagName) {
+		sv, ok := fieldByIndex(src, sf.index, false)
+		if !ok || (sf.opts.Has("omitempty") && sv.IsZero()) {
+			continue
+		}
+		p := path.field(sf.name)
+		v := reflect.New(dst.Type().Elem()).Elem()
+		n := len(c.errs)
+		c.assign(v, sv, p, sf.layout)
+		if len(c.errs) == n {
+			dst.SetMapIndex(reflect.ValueOf(sf.key).Convert(dst.Type().Key()), v)
+		}
+	}
+}
+
+func errCannotConvert(src, dst reflect.Value) error {
+	return fmt.Errorf("cannot convert %s into %s", src.Type(), dst.Type())
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
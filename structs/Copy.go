@@ -0,0 +1,84 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MapReport lists the fields which MapTo couldn't pair up. The fields of
+// nested structs are given by their path, such as "Address.Zip", those of
+// slice and map elements like "Items[].Name".
+type MapReport struct {
+	// UnmappedSource are the source fields not copied anywhere.
+	UnmappedSource []string
+	// UnmappedTarget are the destination fields left untouched as no source
+	// field matches them.
+	UnmappedTarget []string
+}
+
+// Complete returns true if every field of the source and destination structs
+// has been paired up.
+func (r *MapReport) Complete() bool {
+	return len(r.UnmappedSource) == 0 && len(r.UnmappedTarget) == 0
+}
+
+// MapTo copies the fields of s into the struct pointed to by dst, which can be
+// of another type, such as a DB model into an API DTO. A destination field is
+// filled by the source field of the same key, that's the name given by the
+// "structs" tag or the field name, then of the same field name, then of the
+// same key regardless of case. Example:
+//
+//   type User struct {
+//       ID      int64
+//       Name    string `structs:"name"`
+//       Created time.Time
+//   }
+//
+//   type UserDTO struct {
+//       ID      string                     // converted with strconv
+//       Name    *string `structs:"name"`   // pointers and values are interchangeable
+//       Created string  `layout:"2006-01-02"`
+//   }
+//
+//   var dto UserDTO
+//   report, err := structs.New(user).MapTo(&dto)
+//
+// The values are converted as needed between numbers of any width, strings
+// and numbers, times and strings or unix seconds, pointers and values, and
+// the types implementing encoding.TextMarshaler or encoding.TextUnmarshaler.
+// Nested structs, slices, arrays and maps are converted recursively. Slices
+// and maps are copied even if their type is the same, so that dst doesn't
+// share them with s, whereas structs of the same type are assigned as is.
+// Embedded structs and struct fields tagged "flatten" are expanded into the
+// fields of their parent. A source field tagged "omitempty" leaves its
+// destination field untouched if it's empty.
+//
+// The returned report lists the fields which weren't paired up. The error,
+// which is of type Errors, lists every field which couldn't be converted; the
+// other fields are copied nevertheless.
+func (s *Struct) MapTo(dst interface{}) (*MapReport, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, fmt.Errorf("structs: MapTo needs a non-nil pointer, got %T", dst)
+	}
+
+	report := &MapReport{}
+	c := &converter{tagName: s.TagName, report: report}
+	c.assign(v.Elem(), s.value, fieldPath{}, "")
+	return report, c.errs.err()
+}
+
+// MapTo copies the fields of the struct src into the struct pointed to by dst.
+// For more info refer to Struct types MapTo() method. It panics if src's kind
+// is not struct.
+func MapTo(src, dst interface{}) (*MapReport, error) {
+	return New(src).MapTo(dst)
+}
+
+// Copy is the same as MapTo, except that the fields which weren't paired up
+// aren't reported. Note that the destination comes first, like the built-in
+// copy. It panics if src's kind is not struct.
+func Copy(dst, src interface{}) error {
+	_, err := New(src).MapTo(dst)
+	return err
+}
@@ -0,0 +1,231 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Decoder populates structs from maps, such as the ones decoded from JSON,
+// YAML or environment variables, or produced by Map.
+type Decoder struct {
+	// TagName is the tag holding the key and the options of the fields. It's
+	// DefaultTagName by default.
+	TagName string
+	// WeaklyTyped enables the conversions of booleans from and to numbers, of
+	// empty strings to zero values and of single values to slices, on top of
+	// the conversions of strings to numbers and booleans which are always
+	// done. It's true by default.
+	WeaklyTyped bool
+	// ErrorUnused fails the decoding of the map keys which match no field.
+	ErrorUnused bool
+}
+
+// NewDecoder returns a new *Decoder with the default settings.
+func NewDecoder() *Decoder {
+	return &Decoder{
+		TagName:     DefaultTagName,
+		WeaklyTyped: true,
+	}
+}
+
+// Decode is the reverse of Map: it stores the values of input into the
+// fields of the struct pointed to by target. The "structs" key in the struct's
+// field tag value is the key name looked up in input, the field name being
+// used otherwise. A key which doesn't match exactly is matched regardless of
+// case. Example:
+//
+//   // Field is read from key "myName".
+//   Name string `structs:"myName"`
+//
+// A tag value with the content of "-" ignores that particular field. Example:
+//
+//   // Field is never decoded.
+//   Field bool `structs:"-"`
+//
+// A tag value with the option of "omitempty" leaves the field untouched if
+// the input value is empty, so the field keeps its current value. Example:
+//
+//   // Field keeps its value unless input has a non-empty "myName".
+//   Field string `structs:"myName,omitempty"`
+//
+// A tag value with the option of "flatten" reads the fields of a struct field
+// from input itself rather than from a nested map. Embedded structs are
+// flattened too, but they are also decoded from a nested map keyed by their
+// type name, which is how Map outputs them. Example:
+//
+//   // The FieldStruct's fields are read from input.
+//   FieldStruct Address `structs:",flatten"`
+//
+// A tag value with the option of "string" decodes the field from a string,
+// like the output of its String method, using weakly typed conversions
+// whatever the WeaklyTyped setting. An input value which is not a string
+// fails. Types other than numbers and booleans must implement
+// encoding.TextUnmarshaler. Example:
+//
+//   // Field is decoded from a string such as "42".
+//   Field int `structs:"field,string"`
+//
+// Nested maps are decoded into nested structs, pointers to structs, slices
+// and maps recursively; pointers already set are decoded into rather than
+// replaced. The values are converted like MapTo does.
+//
+// Decoding goes on after a field failed, so that the error, which is of type
+// Errors, lists every field which failed.
+func (d *Decoder) Decode(input interface{}, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("structs: Decode needs a non-nil pointer, got %T", target)
+	}
+
+	c := &converter{
+		tagName:     d.TagName,
+		weak:        d.WeaklyTyped,
+		merge:       true,
+		errorUnused: d.ErrorUnused,
+	}
+	c.assign(v.Elem(), reflect.ValueOf(input), fieldPath{}, "")
+	return c.errs.err()
+}
+
+// Decode stores the values of input into the fields of the struct pointed to
+// by target, with the default settings. For more info refer to Decoder types
+// Decode() method.
+func Decode(input interface{}, target interface{}) error {
+	return NewDecoder().Decode(input, target)
+}
+
+// mapToStruct decodes the entries of src into the fields of dst.
+func (c *converter) mapToStruct(dst, src reflect.Value, path fieldPath) {
+	switch src.Type().Key().Kind() {
+	case reflect.String, reflect.Interface:
+	default:
+		c.errs = append(c.errs, &FieldError{Field: path.full, Err: errCannotConvert(src, dst)})
+		return
+	}
+
+	values := make(map[string]reflect.Value, src.Len())
+	folded := make(map[string]string, src.Len())
+	iter := src.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+		if k.Kind() == reflect.Interface {
+			k = k.Elem()
+		}
+		key := fmt.Sprint(k.Interface())
+		values[key] = iter.Value()
+		if _, ok := folded[strings.ToLower(key)]; !ok {
+			folded[strings.ToLower(key)] = key
+		}
+	}
+
+	used := make(map[string]bool, len(values))
+	lookup := func(key string) (string, bool) {
+		if _, ok := values[key]; ok {
+			return key, true
+		}
+		k, ok := folded[strings.ToLower(key)]
+		return k, ok
+	}
+
+	for _, df := range cachedFields(dst.Type(), c.tagName) {
+		key, ok := lookup(df.key)
+		if !ok {
+			continue
+		}
+		used[key] = true
+
+		sv := values[key]
+		if df.opts.Has("omitempty") && isEmptyValue(sv) {
+			continue
+		}
+		p := path.field(df.name)
+		dv, ok := fieldByIndex(dst, df.index, true)
+		if !ok || !dv.CanSet() {
+			c.errs = append(c.errs, &FieldError{Field: p.full, Err: errNotSettable})
+			continue
+		}
+
+		if !df.opts.Has("string") {
+			c.assign(dv, sv, p, df.layout)
+			continue
+		}
+		if sv = indirect(sv); sv.IsValid() && sv.Kind() != reflect.String {
+			c.fail(p, "a string is expected, got %s", sv.Type())
+			continue
+		}
+		weak := c.weak
+		c.weak = true
+		c.assign(dv, sv, p, df.layout)
+		c.weak = weak
+	}
+
+	for _, e := range embeddedFields(dst.Type(), c.tagName) {
+		sv, ok := values[e.Name]
+		if !ok || used[e.Name] {
+			continue
+		}
+		used[e.Name] = true
+		p := path.field(e.Name)
+		dv := dst.FieldByIndex(e.Index)
+		if !dv.CanSet() {
+			c.errs = append(c.errs, &FieldError{Field: p.full, Err: errNotSettable})
+			continue
+		}
+		c.assign(dv, sv, p, "")
+	}
+
+	if c.errorUnused {
+		var unused []string
+		for key := range values {
+			if !used[key] {
+				unused = append(unused, key)
+			}
+		}
+		sort.Strings(unused)
+		for _, key := range unused {
+			c.fail(path.field(key), "no field matches the key %q", key)
+		}
+	}
+}
+
+// embeddedFields returns the embedded struct fields of the struct type t
+// which are flattened by cachedFields.
+func embeddedFields(t reflect.Type, tagName string) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.Anonymous || field.PkgPath != "" {
+			continue
+		}
+		name, opts := parseTag(field.Tag.Get(tagName))
+		if name != "" || opts.Has("flatten") || opts.Has("omitnested") {
+			continue
+		}
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// isEmptyValue returns true if v is nil, a zero value or an empty string,
+// slice or map.
+func isEmptyValue(v reflect.Value) bool {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,177 @@
+package structs
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// SetDefaults sets the fields which have a zero value to the value of their
+// "default" tag. The tag value is converted to the type of the field like
+// Decode does; slices are given as a comma separated list and maps as a comma
+// separated list of key:value pairs. Example:
+//
+//   type Config struct {
+//       Port    int            `default:"8080"`
+//       Timeout time.Duration  `default:"5s"`
+//       Hosts   []string       `default:"a.local,b.local"`
+//       Limits  map[string]int `default:"read:10,write:5"`
+//       DB      *DBConfig      // nested structs get their defaults too
+//   }
+//
+//   cfg := &Config{}
+//   err := structs.New(cfg).SetDefaults()
+//
+// A nil pointer to a struct is set to a new struct only if one of its fields
+// got a default, and never if the struct is of a type it's already within,
+// such as the Next field of a linked list node. The fields of nested and
+// embedded structs are named by their path in the error, which is of type
+// Errors. The struct must have been given to New as a pointer, otherwise its
+// fields can't be set.
+func (s *Struct) SetDefaults() error {
+	var errs Errors
+	w := newFieldWalk(s.value.Type())
+	// the fields are listed regardless of any structs tag, which only
+	// applies to the maps and the conversions
+	setDefaults(w, getFields(s.value, ""), fieldPath{}, &errs)
+	return errs.err()
+}
+
+// SetDefaults sets the zero fields of the struct pointed to by s to the value
+// of their "default" tag. For more info refer to Struct types SetDefaults()
+// method. It panics if s's kind is not struct.
+func SetDefaults(s interface{}) error {
+	return New(s).SetDefaults()
+}
+
+func setDefaults(w *fieldWalk, fields []*Field, path fieldPath, errs *Errors) {
+	for _, f := range fields {
+		if !f.IsExported() && !f.IsEmbedded() {
+			continue
+		}
+		p := path.field(f.Name())
+		def, ok := f.field.Tag.Lookup("default")
+		if !ok {
+			w.nested(f, func(nested []*Field) {
+				setDefaults(w, nested, p, errs)
+			})
+			continue
+		}
+		if !f.IsExported() || !f.IsZero() {
+			continue
+		}
+
+		v, err := parseText(f.value.Type(), def, ",", f.Tag("layout"))
+		if err == nil {
+			err = f.Set(v.Interface())
+		}
+		if err != nil {
+			*errs = append(*errs, &FieldError{Field: p.full, Err: err})
+		}
+	}
+}
+
+// fieldWalk keeps SetDefaults and BindEnv from walking forever into
+// self-referential types and cyclic data.
+type fieldWalk struct {
+	// the struct types from the root to the current field
+	within map[reflect.Type]bool
+	// the pointers already walked into
+	visited map[visit]bool
+}
+
+func newFieldWalk(root reflect.Type) *fieldWalk {
+	return &fieldWalk{
+		within:  map[reflect.Type]bool{root: true},
+		visited: make(map[visit]bool),
+	}
+}
+
+// nested calls fn with the fields of the struct held by f, if f is a struct or
+// a pointer to a struct which isn't decoded from text. A pointer already
+// walked into is skipped. A nil pointer is given a new struct, which is kept
+// only if fn changed it, unless the struct is of a type f is already within.
+func (w *fieldWalk) nested(f *Field, fn func([]*Field)) {
+	t := f.value.Type()
+	switch {
+	case t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textUnmarshalerType):
+		w.enter(t, func() { fn(f.Fields()) })
+	case t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct && !t.Implements(textUnmarshalerType):
+		if !f.value.IsNil() {
+			key := visit{f.value.Pointer(), t}
+			if w.visited[key] {
+				return
+			}
+			w.visited[key] = true
+			w.enter(t.Elem(), func() { fn(f.Fields()) })
+			return
+		}
+		if !f.value.CanSet() || w.within[t.Elem()] {
+			return
+		}
+		n := reflect.New(t.Elem())
+		w.enter(t.Elem(), func() { fn(getFields(n, f.defaultTag)) })
+		if !n.Elem().IsZero() {
+			f.value.Set(n)
+		}
+	}
+}
+
+func (w *fieldWalk) enter(t reflect.Type, fn func()) {
+	was := w.within[t]
+	w.within[t] = true
+	fn()
+	w.within[t] = was
+}
+
+// parseText converts the text of a tag or of an environment variable to a
+// value of type t. The elements of slices and arrays are separated by sep, as
+// are the key:value pairs of maps.
+func parseText(t reflect.Type, text, sep, layout string) (reflect.Value, error) {
+	var src interface{} = text
+
+	elem := t
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if !reflect.PointerTo(elem).Implements(textUnmarshalerType) {
+		switch {
+		case (elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array) && elem.Elem().Kind() != reflect.Uint8:
+			src = splitList(text, sep)
+		case elem.Kind() == reflect.Map:
+			m := make(map[string]string)
+			for _, pair := range splitList(text, sep) {
+				k, v, ok := strings.Cut(pair, ":")
+				if !ok {
+					return reflect.Value{}, fmt.Errorf("invalid map entry %q, key:value expected", pair)
+				}
+				m[strings.TrimSpace(k)] = strings.TrimSpace(v)
+			}
+			src = m
+		}
+	}
+
+	v := reflect.New(t).Elem()
+	c := &converter{weak: true}
+	c.assign(v, reflect.ValueOf(src), fieldPath{}, layout)
+	if len(c.errs) > 0 {
+		return reflect.Value{}, c.errs[0]
+	}
+	return v, nil
+}
+
+// splitList splits text at sep and trims the spaces around the elements. An
+// empty text is an empty list.
+func splitList(text, sep string) []string {
+	if strings.TrimSpace(text) == "" {
+		return []string{}
+	}
+	list := strings.Split(text, sep)
+	for i := range list {
+		list[i] = strings.TrimSpace(list[i])
+	}
+	return list
+}
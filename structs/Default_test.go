@@ -0,0 +1,36 @@
+package structs
+
+import "testing"
+
+type ignoredFields struct {
+	Secret string `structs:"-" env:"SECRET" default:"x"`
+	Nested struct {
+		Token string `structs:"-" env:"TOKEN" default:"y"`
+	} `structs:"-" env:"NESTED"`
+}
+
+func TestSetDefaultsIgnoresStructsTag(t *testing.T) {
+	s := &ignoredFields{}
+	if err := SetDefaults(s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Secret != "x" || s.Nested.Token != "y" {
+		t.Errorf("got %+v, want the defaults of the fields tagged structs:\"-\"", s)
+	}
+}
+
+func TestBindEnvIgnoresStructsTag(t *testing.T) {
+	env := map[string]string{"APP_SECRET": "s", "APP_NESTED_TOKEN": "t"}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	s := &ignoredFields{}
+	if err := New(s).BindEnvFunc("APP_", lookup); err != nil {
+		t.Fatal(err)
+	}
+	if s.Secret != "s" || s.Nested.Token != "t" {
+		t.Errorf("got %+v, want the variables of the fields tagged structs:\"-\"", s)
+	}
+}
@@ -0,0 +1,110 @@
+package structs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// BindEnv sets the fields having an "env" tag to the value of the environment
+// variable it names, prefixed with prefix. The value is converted to the type
+// of the field like Decode does; slices are given as a comma separated list
+// and maps as a comma separated list of key:value pairs. Example:
+//
+//   // Field is set from APP_PORT, if BindEnv is given the prefix "APP_".
+//   Port int `env:"PORT"`
+//
+// A tag value with the option of "required" fails if the variable is not set.
+// Otherwise the field keeps its value, such as the one set by SetDefaults.
+// Example:
+//
+//   // Field must be set from APP_DB_URL.
+//   URL string `env:"DB_URL,required"`
+//
+// A tag value with the option of "sep=..." splits slices and maps at another
+// separator than ','. Example:
+//
+//   // Field is set from APP_PATH, like "/bin:/usr/bin".
+//   Path []string `env:"PATH,sep=:"`
+//
+// The fields of nested and embedded structs are bound too. The name in the
+// "env" tag of a struct field, if any, is added to the prefix of its fields
+// with a '_'. Example:
+//
+//   // The fields of DB are set from APP_DB_HOST, APP_DB_PORT...
+//   DB DBConfig `env:"DB"`
+//
+// A nil pointer to a struct is set to a new struct only if one of its fields
+// got a value, and never if the struct is of a type it's already within. The
+// error, which is of type Errors, lists every field which failed. The struct
+// must have been given to New as a pointer, otherwise its fields can't be set.
+func (s *Struct) BindEnv(prefix string) error {
+	return s.BindEnvFunc(prefix, os.LookupEnv)
+}
+
+// BindEnvFunc is the same as BindEnv, except that the variables are looked up
+// with lookup rather than in the environment.
+func (s *Struct) BindEnvFunc(prefix string, lookup func(key string) (string, bool)) error {
+	var errs Errors
+	w := newFieldWalk(s.value.Type())
+	// the fields are listed regardless of any structs tag, which only
+	// applies to the maps and the conversions
+	bindEnv(w, getFields(s.value, ""), prefix, lookup, fieldPath{}, &errs)
+	return errs.err()
+}
+
+// BindEnv sets the fields of the struct pointed to by s from the environment
+// variables named by their "env" tag. For more info refer to Struct types
+// BindEnv() method. It panics if s's kind is not struct.
+func BindEnv(s interface{}, prefix string) error {
+	return New(s).BindEnv(prefix)
+}
+
+func bindEnv(w *fieldWalk, fields []*Field, prefix string, lookup func(string) (string, bool), path fieldPath, errs *Errors) {
+	for _, f := range fields {
+		if !f.IsExported() && !f.IsEmbedded() {
+			continue
+		}
+		name, opts := parseTag(f.Tag("env"))
+		if name == "-" {
+			continue
+		}
+		p := path.field(f.Name())
+
+		nested := false
+		nestedPrefix := prefix
+		if name != "" {
+			nestedPrefix = prefix + name + "_"
+		}
+		w.nested(f, func(fields []*Field) {
+			nested = true
+			bindEnv(w, fields, nestedPrefix, lookup, p, errs)
+		})
+		if nested || name == "" || !f.IsExported() {
+			continue
+		}
+
+		key := prefix + name
+		text, ok := lookup(key)
+		if !ok {
+			if opts.Has("required") {
+				*errs = append(*errs, &FieldError{Field: p.full, Err: fmt.Errorf("environment variable %s is required", key)})
+			}
+			continue
+		}
+
+		sep := ","
+		for _, opt := range opts {
+			if strings.HasPrefix(opt, "sep=") && len(opt) > len("sep=") {
+				sep = opt[len("sep="):]
+			}
+		}
+		v, err := parseText(f.value.Type(), text, sep, f.Tag("layout"))
+		if err == nil {
+			err = f.Set(v.Interface())
+		}
+		if err != nil {
+			*errs = append(*errs, &FieldError{Field: p.full, Err: fmt.Errorf("environment variable %s: %v", key, err)})
+		}
+	}
+}
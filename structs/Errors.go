@@ -0,0 +1,58 @@
+package structs
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FieldError is the failure of a single field, located by its path such as
+// "Address.Lines[2]".
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Err.Error()
+	}
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause of the failure.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors lists every field which failed, in field order. The functions of
+// this package return it as their error when at least one field failed, so it
+// can be retrieved with errors.As.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	if len(e) == 1 {
+		return "structs: " + e[0].Error()
+	}
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "structs: " + strconv.Itoa(len(e)) + " errors: " + strings.Join(msgs, "; ")
+}
+
+// Fields returns the paths of the fields which failed.
+func (e Errors) Fields() []string {
+	fields := make([]string, len(e))
+	for i, err := range e {
+		fields[i] = err.Field
+	}
+	return fields
+}
+
+// err returns e as an error, or nil if it's empty.
+func (e Errors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
@@ -274,8 +274,9 @@ func getFields(v reflect.Value, tagName string) []*Field {
 		}
 
 		f := &Field{
-			field: field,
-			value: v.FieldByName(field.Name),
+			field:      field,
+			value:      v.FieldByName(field.Name),
+			defaultTag: tagName,
 		}
 
 		fields = append(fields, f)
@@ -0,0 +1,338 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	// ValidateTagName is the tag holding the validation rules of the fields.
+	ValidateTagName = "validate"
+)
+
+// Rule checks the value of a field against the parameter of the rule, such
+// as "3" for "min=3", and returns an error explaining why the value is
+// invalid, or nil if it's valid. parent is the struct holding the field, for
+// the rules comparing several fields. The value given to a rule is never a
+// pointer nor an interface: the rules don't apply to nil values, which only
+// "required" rejects.
+type Rule func(value reflect.Value, param string, parent reflect.Value) error
+
+// RuleError is the failure of a validation rule. The Err of a FieldError
+// returned by Validate is a *RuleError.
+type RuleError struct {
+	Rule  string
+	Param string
+	Err   error
+}
+
+func (e *RuleError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error of the rule.
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	rulesMutex sync.RWMutex
+	rules      = map[string]Rule{}
+)
+
+// RegisterRule makes a rule usable in validate tags under name. Registering a
+// rule with the name of another one, built-in rules included, replaces it. It
+// panics if name is empty, contains a ',' or '=', or is one of the keywords
+// "required", "omitempty" and "dive".
+func RegisterRule(name string, rule Rule) {
+	switch {
+	case name == "" || strings.ContainsAny(name, ",="):
+		panic(fmt.Errorf("invalid rule name: %q", name))
+	case name == "required" || name == "omitempty" || name == "dive":
+		panic(fmt.Errorf("rule name %q is reserved", name))
+	case rule == nil:
+		panic(fmt.Errorf("rule %q is nil", name))
+	}
+	rulesMutex.Lock()
+	rules[name] = rule
+	rulesMutex.Unlock()
+}
+
+func lookupRule(name string) Rule {
+	rulesMutex.RLock()
+	rule := rules[name]
+	rulesMutex.RUnlock()
+	if rule == nil {
+		panic(fmt.Errorf("unknown validation rule: %q", name))
+	}
+	return rule
+}
+
+// Validate checks the fields of the struct against the rules of their
+// "validate" tag. The rules are separated by commas and their parameter
+// follows a '='; a comma within a parameter is escaped with a backslash,
+// which is written "\\," in the tag as the tag value is a quoted string, like
+// in the Level field below. Example:
+//
+//   type Request struct {
+//       Name  string   `validate:"required,min=3,max=32"`
+//       Email string   `validate:"omitempty,email"`
+//       Kind  string   `validate:"oneof=user admin"`
+//       Code  string   `validate:"regex=^[A-Z]{3}$"`
+//       Level string   `validate:"regex=^L{1\\,3}$"`
+//       Tags  []string `validate:"max=10,dive,required,max=16"`
+//       From  int
+//       To    int      `validate:"gtefield=From"`
+//   }
+//
+// The built-in rules are:
+//
+//   required      the value is not zero, nil or empty
+//   omitempty     skips the other rules if the value is empty
+//   min, max      bounds of a number, of a duration like "min=1s", or of the
+//   gt, lt        length of a string (in runes), a slice or a map; gte and
+//   gte, lte      lte are aliases of min and max
+//   len           the exact length, or the exact number
+//   eq, ne        equality of a number, a string or a boolean
+//   oneof         the value is one of the words of the parameter, like
+//                 "oneof=red green blue"
+//   regex         a string matches the regular expression
+//   email, url    a string is an e-mail address or an absolute URL
+//   ip, ipv4,     a string is an IP address
+//   ipv6
+//   eqfield,      comparisons with another field of the same struct holding
+//   nefield,      a number, a string or a time.Time, like "gtfield=Start"
+//   gtfield,
+//   gtefield,
+//   ltfield,
+//   ltefield
+//   dive          the next rules apply to the elements of a slice, an array
+//                 or a map rather than to itself
+//
+// Others can be added with RegisterRule. Nested structs, pointers to structs
+// and the structs held by slices, arrays and maps are validated recursively,
+// whether they have rules or not. A field tagged `validate:"-"` is neither
+// validated nor walked into. A struct reached through the same pointer or map
+// several times is only walked into the first time, so cyclic data such as a
+// tree with parent pointers is fine.
+//
+// The error, which is of type Errors, lists every invalid field with the
+// first rule it fails, as a *RuleError. The fields are named by their path,
+// such as "Items[2].Name". It panics if a rule is unknown, has an invalid
+// parameter or is used on a type it doesn't apply to, as these are mistakes
+// in the tags.
+func (s *Struct) Validate() error {
+	v := &validation{}
+	v.validateStruct(s.value, fieldPath{})
+	return v.errs.err()
+}
+
+// Validate checks the fields of the struct against the rules of their
+// "validate" tag. For more info refer to Struct types Validate() method. It
+// panics if s's kind is not struct.
+func Validate(s interface{}) error {
+	return New(s).Validate()
+}
+
+type ruleCall struct {
+	name  string
+	param string
+}
+
+// ruleLevels are the rules of a field, then the rules of its elements after
+// the first "dive", and so on.
+type ruleLevels [][]ruleCall
+
+var parsedRules sync.Map // string -> ruleLevels
+
+func parseRules(tag string) ruleLevels {
+	if r, ok := parsedRules.Load(tag); ok {
+		return r.(ruleLevels)
+	}
+
+	levels := ruleLevels{nil}
+	for _, term := range splitRules(tag) {
+		if term == "" {
+			continue
+		}
+		name, param, _ := strings.Cut(term, "=")
+		call := ruleCall{name: name, param: param}
+		switch name {
+		case "dive":
+			levels = append(levels, nil)
+			continue
+		case "required", "omitempty":
+		default:
+			// fails early on unknown rules
+			lookupRule(name)
+		}
+		levels[len(levels)-1] = append(levels[len(levels)-1], call)
+	}
+
+	r, _ := parsedRules.LoadOrStore(tag, levels)
+	return r.(ruleLevels)
+}
+
+// splitRules splits tag at the commas which are not escaped.
+func splitRules(tag string) []string {
+	var terms []string
+	var term strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			term.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			terms = append(terms, term.String())
+			term.Reset()
+		default:
+			term.WriteByte(tag[i])
+		}
+	}
+	return append(terms, term.String())
+}
+
+type validation struct {
+	errs Errors
+	// the pointers and maps already walked into, so that cyclic data such as
+	// trees with parent pointers is walked only once
+	visited map[visit]bool
+	depth   int
+}
+
+type visit struct {
+	ptr uintptr
+	t   reflect.Type
+}
+
+// visit returns true the first time it's given a pointer or a map.
+func (v *validation) visit(value reflect.Value) bool {
+	key := visit{value.Pointer(), value.Type()}
+	if v.visited[key] {
+		return false
+	}
+	if v.visited == nil {
+		v.visited = make(map[visit]bool)
+	}
+	v.visited[key] = true
+	return true
+}
+
+func (v *validation) validateStruct(s reflect.Value, path fieldPath) {
+	// the fields are listed regardless of any structs tag, as the structs tag
+	// name of a Struct is not relevant to validation
+	for _, f := range cachedFields(s.Type(), "") {
+		tag := f.field.Tag.Get(ValidateTagName)
+		if tag == "-" {
+			continue
+		}
+		value, ok := fieldByIndex(s, f.index, false)
+		if !ok {
+			continue
+		}
+		v.validateValue(value, parseRules(tag), 0, path.field(f.name), s)
+	}
+}
+
+func (v *validation) validateValue(value reflect.Value, levels ruleLevels, depth int, path fieldPath, parent reflect.Value) {
+	if depth < len(levels) {
+		for _, call := range levels[depth] {
+			switch call.name {
+			case "omitempty":
+				if isEmptyValue(value) {
+					return
+				}
+				continue
+			case "required":
+				if isEmptyValue(value) {
+					v.fail(path, call, fmt.Errorf("is required"))
+					return
+				}
+				continue
+			}
+
+			target := indirect(value)
+			if !target.IsValid() {
+				continue
+			}
+			if err := lookupRule(call.name)(target, call.param, parent); err != nil {
+				v.fail(path, call, err)
+				return
+			}
+		}
+	}
+
+	// slices held by interfaces can still make cycles, which end here
+	if v.depth >= maxDepth {
+		return
+	}
+	v.depth++
+	defer func() { v.depth-- }()
+
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() || (value.Kind() == reflect.Ptr && !v.visit(value)) {
+			return
+		}
+		value = value.Elem()
+	}
+	dive := depth+1 < len(levels)
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type() != timeType {
+			v.validateStruct(value, path)
+		}
+	case reflect.Slice, reflect.Array:
+		if !dive && !mayHoldStructs(value.Type().Elem()) {
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			v.validateValue(value.Index(i), levels, depth+1, path.index(i), parent)
+		}
+	case reflect.Map:
+		if (!dive && !mayHoldStructs(value.Type().Elem())) || !v.visit(value) {
+			return
+		}
+		iter := value.MapRange()
+		for iter.Next() {
+			v.validateValue(iter.Value(), levels, depth+1, path.index(iter.Key()), parent)
+		}
+	}
+}
+
+func (v *validation) fail(path fieldPath, call ruleCall, err error) {
+	v.errs = append(v.errs, &FieldError{
+		Field: path.full,
+		Err:   &RuleError{Rule: call.name, Param: call.param, Err: err},
+	})
+}
+
+// indirect returns the value pointed to by v through any pointers and
+// interfaces, or an invalid value if one of them is nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// mayHoldStructs returns true if the values of type t may be or hold structs
+// to validate.
+func mayHoldStructs(t reflect.Type) bool {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		case reflect.Interface:
+			return true
+		case reflect.Struct:
+			return t != timeType
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+func init() {
+	for name, op := range map[string]string{
+		"min": ">=", "gte": ">=", "max": "<=", "lte": "<=",
+		"gt": ">", "lt": "<", "len": "==",
+	} {
+		RegisterRule(name, sizeRule(op, false))
+	}
+	RegisterRule("eq", sizeRule("==", true))
+	RegisterRule("ne", sizeRule("!=", true))
+	for name, op := range map[string]string{
+		"eqfield": "==", "nefield": "!=", "gtfield": ">", "gtefield": ">=", "ltfield": "<", "ltefield": "<=",
+	} {
+		RegisterRule(name, fieldRule(op))
+	}
+	RegisterRule("oneof", oneOf)
+	RegisterRule("regex", matchRegex)
+	RegisterRule("email", stringRule("email", isEmail, "must be a valid e-mail address"))
+	RegisterRule("url", stringRule("url", isURL, "must be a valid URL"))
+	RegisterRule("ip", stringRule("ip", func(s string) bool { return net.ParseIP(s) != nil }, "must be a valid IP address"))
+	RegisterRule("ipv4", stringRule("ipv4", func(s string) bool {
+		return net.ParseIP(s) != nil && !strings.Contains(s, ":")
+	}, "must be a valid IPv4 address"))
+	RegisterRule("ipv6", stringRule("ipv6", func(s string) bool {
+		return net.ParseIP(s) != nil && strings.Contains(s, ":")
+	}, "must be a valid IPv6 address"))
+}
+
+var (
+	// phrases of the comparisons of numbers
+	valuePhrases = map[string]string{
+		">=": "at least", "<=": "at most", ">": "greater than", "<": "less than", "==": "equal to", "!=": "different from",
+	}
+	// phrases of the comparisons of lengths
+	lengthPhrases = map[string]string{
+		">=": "at least", "<=": "at most", ">": "more than", "<": "fewer than", "==": "exactly", "!=": "other than",
+	}
+	// phrases of the comparisons of fields
+	fieldPhrases = map[string]string{
+		">=": "greater than or equal to", "<=": "less than or equal to", ">": "greater than", "<": "less than",
+		"==": "equal to", "!=": "different from",
+	}
+)
+
+func holds(op string, c int) bool {
+	switch op {
+	case ">=":
+		return c >= 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case "<":
+		return c < 0
+	case "==":
+		return c == 0
+	default:
+		return c != 0
+	}
+}
+
+// sizeRule compares numbers with the parameter, and the lengths of strings,
+// slices, arrays and maps. If byValue is true, strings and booleans are
+// compared themselves rather than their length.
+func sizeRule(op string, byValue bool) Rule {
+	return func(v reflect.Value, param string, _ reflect.Value) error {
+		if byValue {
+			var c int
+			switch v.Kind() {
+			case reflect.String:
+				c = strings.Compare(v.String(), param)
+			case reflect.Bool:
+				b, err := strconv.ParseBool(param)
+				if err != nil {
+					panic(fmt.Errorf("invalid boolean parameter %q: %v", param, err))
+				}
+				if v.Bool() != b {
+					c = 1
+				}
+			}
+			if v.Kind() == reflect.String || v.Kind() == reflect.Bool {
+				if !holds(op, c) {
+					return fmt.Errorf("must be %s %s", valuePhrases[op], param)
+				}
+				return nil
+			}
+		}
+
+		var c int
+		unit := ""
+		switch {
+		case v.Type() == durationType:
+			d, err := time.ParseDuration(param)
+			if err != nil {
+				panic(fmt.Errorf("invalid duration parameter %q: %v", param, err))
+			}
+			c = compareInts(v.Int(), int64(d))
+		case isInt(v.Kind()):
+			c = compareFloats(float64(v.Int()), parseFloatParam(param))
+		case isUint(v.Kind()):
+			c = compareFloats(float64(v.Uint()), parseFloatParam(param))
+		case isFloat(v.Kind()):
+			c = compareFloats(v.Float(), parseFloatParam(param))
+		case v.Kind() == reflect.String:
+			c = compareInts(int64(utf8.RuneCountInString(v.String())), parseIntParam(param))
+			unit = "characters"
+		case v.Kind() == reflect.Slice || v.Kind() == reflect.Array || v.Kind() == reflect.Map:
+			c = compareInts(int64(v.Len()), parseIntParam(param))
+			unit = "elements"
+		default:
+			panic(fmt.Errorf("cannot compare %s with %q", v.Type(), param))
+		}
+		if holds(op, c) {
+			return nil
+		}
+		if unit == "" {
+			return fmt.Errorf("must be %s %s", valuePhrases[op], param)
+		}
+		return fmt.Errorf("must have %s %s %s", lengthPhrases[op], param, unit)
+	}
+}
+
+// fieldRule compares the value with another field of the same struct, named
+// by the parameter.
+func fieldRule(op string) Rule {
+	return func(v reflect.Value, param string, parent reflect.Value) error {
+		other := parent
+		for _, name := range strings.Split(param, ".") {
+			other = indirect(other)
+			if !other.IsValid() {
+				return fmt.Errorf("must be %s %s, which is nil", fieldPhrases[op], param)
+			}
+			if other.Kind() != reflect.Struct {
+				panic(fmt.Errorf("cannot look up field %q in %s", param, parent.Type()))
+			}
+			other = other.FieldByName(name)
+			if !other.IsValid() {
+				panic(fmt.Errorf("no field %q in %s", param, parent.Type()))
+			}
+		}
+		other = indirect(other)
+		if !other.IsValid() {
+			return fmt.Errorf("must be %s %s, which is nil", fieldPhrases[op], param)
+		}
+
+		c, ok := compareValues(v, other)
+		if !ok {
+			if op != "==" && op != "!=" {
+				panic(fmt.Errorf("cannot compare %s with %s", v.Type(), other.Type()))
+			}
+			c = 1
+			if other.Type() == v.Type() && reflect.DeepEqual(v.Interface(), other.Interface()) {
+				c = 0
+			}
+		}
+		if !holds(op, c) {
+			return fmt.Errorf("must be %s %s", fieldPhrases[op], param)
+		}
+		return nil
+	}
+}
+
+// compareValues compares numbers of any types, strings and times. The
+// boolean is false if a and b are of other types.
+func compareValues(a, b reflect.Value) (int, bool) {
+	switch {
+	case a.Type() == timeType && b.Type() == timeType:
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), true
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), true
+	case isInt(a.Kind()) && isInt(b.Kind()):
+		return compareInts(a.Int(), b.Int()), true
+	case isUint(a.Kind()) && isUint(b.Kind()):
+		return compareUints(a.Uint(), b.Uint()), true
+	}
+	x, ok := floatOf(a)
+	if !ok {
+		return 0, false
+	}
+	y, ok := floatOf(b)
+	if !ok {
+		return 0, false
+	}
+	return compareFloats(x, y), true
+}
+
+func floatOf(v reflect.Value) (float64, bool) {
+	switch {
+	case isInt(v.Kind()):
+		return float64(v.Int()), true
+	case isUint(v.Kind()):
+		return float64(v.Uint()), true
+	case isFloat(v.Kind()):
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareUints(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func parseFloatParam(param string) float64 {
+	f, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic(fmt.Errorf("invalid number parameter %q: %v", param, err))
+	}
+	return f
+}
+
+func parseIntParam(param string) int64 {
+	n, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		panic(fmt.Errorf("invalid length parameter %q: %v", param, err))
+	}
+	return n
+}
+
+// oneOf accepts the values whose string form is one of the words of the
+// parameter.
+func oneOf(v reflect.Value, param string, _ reflect.Value) error {
+	var s string
+	switch {
+	case v.Kind() == reflect.String:
+		s = v.String()
+	case isInt(v.Kind()):
+		s = strconv.FormatInt(v.Int(), 10)
+	case isUint(v.Kind()):
+		s = strconv.FormatUint(v.Uint(), 10)
+	default:
+		panic(fmt.Errorf("oneof doesn't apply to %s", v.Type()))
+	}
+	for _, word := range strings.Fields(param) {
+		if s == word {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(strings.Fields(param), ", "))
+}
+
+var regexps sync.Map // string -> *regexp.Regexp
+
+func matchRegex(v reflect.Value, param string, _ reflect.Value) error {
+	r, ok := regexps.Load(param)
+	if !ok {
+		r, _ = regexps.LoadOrStore(param, regexp.MustCompile(param))
+	}
+	if !r.(*regexp.Regexp).MatchString(stringOf(v, "regex")) {
+		return fmt.Errorf("must match %s", param)
+	}
+	return nil
+}
+
+func stringRule(name string, valid func(string) bool, message string) Rule {
+	err := errors.New(message)
+	return func(v reflect.Value, _ string, _ reflect.Value) error {
+		if !valid(stringOf(v, name)) {
+			return err
+		}
+		return nil
+	}
+}
+
+func stringOf(v reflect.Value, rule string) string {
+	if v.Kind() != reflect.String {
+		panic(fmt.Errorf("%s doesn't apply to %s", rule, v.Type()))
+	}
+	return v.String()
+}
+
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+}
@@ -0,0 +1,87 @@
+package task
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff computes how long to wait before the next attempt of a retried operation.
+type Backoff interface {
+	// Delay returns the delay before attempt+1, given that attempt (starting from 1) just failed and
+	// the previous delay was previous (0 for the first failure).
+	Delay(attempt int, previous time.Duration) time.Duration
+}
+
+// BackoffFunc is an adapter to allow the use of ordinary functions as Backoff.
+type BackoffFunc func(attempt int, previous time.Duration) time.Duration
+
+// Delay calls f(attempt, previous).
+func (f BackoffFunc) Delay(attempt int, previous time.Duration) time.Duration {
+	return f(attempt, previous)
+}
+
+// ConstantBackoff waits the same delay between all the attempts.
+func ConstantBackoff(delay time.Duration) Backoff {
+	return BackoffFunc(func(int, time.Duration) time.Duration {
+		return delay
+	})
+}
+
+// LinearBackoff waits initial after the first failure and step more after every next failure,
+// never more than max. A max of 0 means no upper bound.
+func LinearBackoff(initial, step, max time.Duration) Backoff {
+	return BackoffFunc(func(attempt int, _ time.Duration) time.Duration {
+		return capDelay(initial+time.Duration(attempt-1)*step, max)
+	})
+}
+
+// ExponentialBackoff waits initial after the first failure and multiplies the delay by multiplier
+// after every next failure, never more than max. A max of 0 means no upper bound.
+// A multiplier less than or equal to 1 defaults to 2.
+func ExponentialBackoff(initial time.Duration, multiplier float64, max time.Duration) Backoff {
+	if multiplier <= 1 {
+		multiplier = 2
+	}
+	return BackoffFunc(func(attempt int, _ time.Duration) time.Duration {
+		delay := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+		// compared as floats, as float64(math.MaxInt64) is 2^63, which overflows a Duration
+		if max > 0 && delay >= float64(max) {
+			return max
+		}
+		if delay >= math.MaxInt64 {
+			return time.Duration(math.MaxInt64)
+		}
+		return capDelay(time.Duration(delay), max)
+	})
+}
+
+// DecorrelatedJitterBackoff waits a random delay between base and three times the previous delay,
+// never more than max. A max of 0 means no upper bound.
+// It spreads the retries of concurrent clients better than a plain exponential backoff.
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+func DecorrelatedJitterBackoff(base, max time.Duration) Backoff {
+	return BackoffFunc(func(_ int, previous time.Duration) time.Duration {
+		if previous < base {
+			previous = base
+		}
+		upper := time.Duration(math.MaxInt64)
+		if previous <= math.MaxInt64/3 {
+			upper = previous * 3
+		}
+		if upper <= base {
+			return capDelay(base, max)
+		}
+		return capDelay(base+time.Duration(rand.Int63n(int64(upper-base))), max)
+	})
+}
+
+func capDelay(delay, max time.Duration) time.Duration {
+	if delay < 0 {
+		delay = 0
+	}
+	if max > 0 && delay > max {
+		return max
+	}
+	return delay
+}
@@ -0,0 +1,207 @@
+package task
+
+import (
+	"context"
+	"sync"
+)
+
+// Generate returns a channel that emits the given values in order and is closed afterwards, or as soon
+// as ctx is done.
+func Generate[T any](ctx context.Context, values ...T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range values {
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FanIn merges the given channels into one channel, which is closed once all the input channels are
+// closed or ctx is done. The order of the values between different input channels is not preserved.
+//
+// Example:
+//
+//   for v := range task.FanIn(ctx, ch1, ch2, ch3) {
+//       fmt.Println(v)
+//   }
+func FanIn[T any](ctx context.Context, cs ...<-chan T) <-chan T {
+	out := make(chan T)
+	MergeTo(ctx, cs, nil, out)
+	return out
+}
+
+// MergeTo forwards every value of the given channels to out, applying transformFn first if it's not nil.
+// out is closed once all the input channels are closed or ctx is done. MergeTo doesn't block.
+func MergeTo[T any](ctx context.Context, cs []<-chan T, transformFn func(T) T, out chan<- T) {
+	var wg sync.WaitGroup
+
+	for _, c := range cs {
+		wg.Add(1)
+		go func(c <-chan T) {
+			defer wg.Done()
+			for {
+				n, ok := receive(ctx, c)
+				if !ok {
+					return
+				}
+				if transformFn != nil {
+					n = transformFn(n)
+				}
+				select {
+				case out <- n:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(c)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+}
+
+// FanOut distributes the values of in over n channels: each value is delivered to exactly one of the
+// returned channels, whichever is ready to receive first. All the returned channels are closed once in
+// is closed or ctx is done.
+func FanOut[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	if n <= 0 {
+		n = 1
+	}
+	outs := make([]chan T, n)
+	result := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		result[i] = outs[i]
+	}
+
+	for _, out := range outs {
+		go func(out chan<- T) {
+			defer close(out)
+			for {
+				v, ok := receive(ctx, in)
+				if !ok {
+					return
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(out)
+	}
+	return result
+}
+
+// Broadcast copies every value of in to each of the n returned channels. A slow receiver slows down
+// all the others. All the returned channels are closed once in is closed or ctx is done.
+func Broadcast[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	if n <= 0 {
+		n = 1
+	}
+	outs := make([]chan T, n)
+	result := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		result[i] = outs[i]
+	}
+
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+		for {
+			v, ok := receive(ctx, in)
+			if !ok {
+				return
+			}
+			for _, out := range outs {
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return result
+}
+
+// Stage is a pipeline stage: it applies fn to every value of in using the given number of workers
+// and emits the results on the returned channel, which is closed once in is closed or ctx is done.
+// With more than one worker the output order is not preserved.
+//
+// Example:
+//
+//   nums := task.Generate(ctx, 1, 2, 3, 4)
+//   squares := task.Stage(ctx, nums, 2, func(n int) int { return n * n })
+//   strs := task.Stage(ctx, squares, 1, strconv.Itoa)
+func Stage[In, Out any](ctx context.Context, in <-chan In, workers int, fn func(In) Out) <-chan Out {
+	if workers <= 0 {
+		workers = 1
+	}
+	out := make(chan Out)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				v, ok := receive(ctx, in)
+				if !ok {
+					return
+				}
+				select {
+				case out <- fn(v):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// receive receives a value from c. The boolean is false if c is closed or ctx is done, so that an idle
+// input channel doesn't keep a goroutine waiting after ctx is done.
+func receive[T any](ctx context.Context, c <-chan T) (T, bool) {
+	select {
+	case v, ok := <-c:
+		return v, ok
+	case <-ctx.Done():
+		var zero T
+		return zero, false
+	}
+}
+
+// Collect receives from c until it's closed or ctx is done and returns all the received values.
+func Collect[T any](ctx context.Context, c <-chan T) []T {
+	var values []T
+	for {
+		select {
+		case v, ok := <-c:
+			if !ok {
+				return values
+			}
+			values = append(values, v)
+		case <-ctx.Done():
+			return values
+		}
+	}
+}
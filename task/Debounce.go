@@ -0,0 +1,207 @@
+package task
+
+import (
+	"sync"
+	"time"
+)
+
+// Edge tells on which edge of a burst of calls a Debouncer or a Throttler invokes its function.
+// Leading and Trailing can be combined.
+type Edge uint8
+
+const (
+	// Leading invokes the function at the first call of a burst.
+	Leading Edge = 1 << iota
+	// Trailing invokes the function at the end of a burst.
+	Trailing
+)
+
+// NewDebouncer is the only way to get a new, ready-to-use Debouncer.
+//
+//   wait: the quiet period after the last call that ends a burst
+//   f: the function to debounce
+//   edge: Leading, Trailing or Leading|Trailing. 0 defaults to Trailing.
+//
+// Example:
+//
+//   // reloads the config once the editor stopped writing the file for 200ms
+//   d := task.NewDebouncer(200*time.Millisecond, reloadConfig, task.Trailing)
+//   for range watcher.Events {
+//       d.Call()
+//   }
+func NewDebouncer(wait time.Duration, f func(), edge Edge) *Debouncer {
+	if edge == 0 {
+		edge = Trailing
+	}
+	return &Debouncer{wait: wait, f: f, edge: edge}
+}
+
+// Debouncer is a goroutine-safe wrapper which coalesces a burst of calls into at most one invocation
+// on each edge. A burst ends once no call happened for the wait duration.
+type Debouncer struct {
+	lock    sync.Mutex
+	wait    time.Duration
+	f       func()
+	edge    Edge
+	timer   *time.Timer
+	gen     uint64
+	pending bool
+}
+
+// Call notifies the Debouncer of a call. It never blocks, except when invoking f on the leading edge.
+func (d *Debouncer) Call() {
+	d.lock.Lock()
+	invoke := false
+	if d.timer == nil && d.edge&Leading != 0 {
+		invoke = true
+	} else {
+		d.pending = true
+	}
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.gen++
+	gen := d.gen
+	d.timer = time.AfterFunc(d.wait, func() { d.fire(gen) })
+	d.lock.Unlock()
+
+	if invoke {
+		d.f()
+	}
+}
+
+// Cancel drops the pending trailing invocation, if any.
+func (d *Debouncer) Cancel() {
+	d.lock.Lock()
+	d.reset()
+	d.lock.Unlock()
+}
+
+// Flush invokes the pending trailing invocation immediately, if any.
+func (d *Debouncer) Flush() {
+	d.lock.Lock()
+	invoke := d.pending && d.edge&Trailing != 0
+	d.reset()
+	d.lock.Unlock()
+
+	if invoke {
+		d.f()
+	}
+}
+
+func (d *Debouncer) fire(gen uint64) {
+	d.lock.Lock()
+	if gen != d.gen {
+		d.lock.Unlock()
+		return
+	}
+	invoke := d.pending && d.edge&Trailing != 0
+	d.timer = nil
+	d.pending = false
+	d.lock.Unlock()
+
+	if invoke {
+		d.f()
+	}
+}
+
+func (d *Debouncer) reset() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.gen++
+	d.pending = false
+}
+
+// NewThrottler is the only way to get a new, ready-to-use Throttler.
+//
+//   interval: the minimum time between two invocations of f
+//   f: the function to throttle
+//   edge: Leading, Trailing or Leading|Trailing. 0 defaults to Leading|Trailing.
+//
+// Example:
+//
+//   // redraws the progress bar at most 10 times per second
+//   t := task.NewThrottler(100*time.Millisecond, redraw, task.Leading|task.Trailing)
+//   for chunk := range chunks {
+//       written += len(chunk)
+//       t.Call()
+//   }
+func NewThrottler(interval time.Duration, f func(), edge Edge) *Throttler {
+	if edge == 0 {
+		edge = Leading | Trailing
+	}
+	return &Throttler{interval: interval, f: f, edge: edge}
+}
+
+// Throttler is a goroutine-safe wrapper which invokes its function at most once per interval.
+type Throttler struct {
+	lock     sync.Mutex
+	interval time.Duration
+	f        func()
+	edge     Edge
+	timer    *time.Timer
+	gen      uint64
+	pending  bool
+}
+
+// Call notifies the Throttler of a call. It never blocks, except when invoking f on the leading edge.
+func (t *Throttler) Call() {
+	t.lock.Lock()
+	invoke := false
+	if t.timer == nil {
+		if t.edge&Leading != 0 {
+			invoke = true
+		} else {
+			t.pending = true
+		}
+		t.startWindow()
+	} else {
+		t.pending = true
+	}
+	t.lock.Unlock()
+
+	if invoke {
+		t.f()
+	}
+}
+
+// Cancel drops the pending trailing invocation, if any, and ends the current interval.
+func (t *Throttler) Cancel() {
+	t.lock.Lock()
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	t.gen++
+	t.pending = false
+	t.lock.Unlock()
+}
+
+func (t *Throttler) startWindow() {
+	t.gen++
+	gen := t.gen
+	t.timer = time.AfterFunc(t.interval, func() { t.fire(gen) })
+}
+
+func (t *Throttler) fire(gen uint64) {
+	t.lock.Lock()
+	if gen != t.gen {
+		t.lock.Unlock()
+		return
+	}
+	invoke := t.pending && t.edge&Trailing != 0
+	t.pending = false
+	if invoke {
+		// the trailing invocation opens a new interval
+		t.startWindow()
+	} else {
+		t.timer = nil
+	}
+	t.lock.Unlock()
+
+	if invoke {
+		t.f()
+	}
+}
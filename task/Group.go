@@ -0,0 +1,109 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Group is a collection of goroutines working on subtasks that are part of the same overall task.
+// The first subtask that returns a non-nil error cancels the Group's context, and that error is
+// the one returned by Wait.
+//
+// A zero Group is valid, has no concurrency limit and does not cancel on error.
+type Group struct {
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	sem     chan struct{}
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup is the usual way to get a new, ready-to-use Group.
+// It returns the Group and a derived context which is canceled the first time a function passed to
+// Go returns a non-nil error or the first time Wait returns, whichever occurs first.
+//
+// Example:
+//
+//   g, ctx := task.NewGroup(context.Background())
+//   g.SetLimit(8)
+//   for _, url := range urls {
+//       url := url
+//       g.Go(func() error { return fetch(ctx, url) })
+//   }
+//   err := g.Wait()
+func NewGroup(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetLimit limits the number of active goroutines in this Group to at most n.
+// A negative value indicates no limit.
+// It panics if called while any goroutine in the Group is active.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("task: modify limit while %v goroutines in the group are still active", len(g.sem)))
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go calls the given function in a new goroutine.
+// It blocks until the new goroutine can be added without the number of active goroutines in the
+// Group exceeding the configured limit.
+func (g *Group) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(f)
+}
+
+// TryGo calls the given function in a new goroutine only if the number of active goroutines in the
+// Group is currently below the configured limit.
+// Return value: true if the goroutine was started and false otherwise.
+func (g *Group) TryGo(f func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(f)
+	return true
+}
+
+// Wait blocks until all function calls from the Go method have returned, then returns the first
+// non-nil error (if any) from them.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	return g.err
+}
+
+func (g *Group) start(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		if err := f(); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel()
+				}
+			})
+		}
+	}()
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
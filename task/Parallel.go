@@ -0,0 +1,84 @@
+package task
+
+import (
+	"context"
+	"runtime"
+)
+
+// ParallelMap applies fn to every element of items using at most workers goroutines and returns the
+// results in the same order as items.
+// If workers is less than or equal to 0, runtime.GOMAXPROCS(0) workers are used.
+// The first error returned by fn cancels the context passed to the remaining calls and is returned,
+// together with a nil result slice.
+//
+// Example:
+//
+//   sizes, err := task.ParallelMap(ctx, paths, 4, func(ctx context.Context, path string) (int64, error) {
+//       info, err := os.Stat(path)
+//       if err != nil {
+//           return 0, err
+//       }
+//       return info.Size(), nil
+//   })
+func ParallelMap[T, R any](ctx context.Context, items []T, workers int, fn func(context.Context, T) (R, error)) ([]R, error) {
+	results := make([]R, len(items))
+	err := ParallelForEachIndex(ctx, items, workers, func(ctx context.Context, index int, item T) error {
+		result, err := fn(ctx, item)
+		if err != nil {
+			return err
+		}
+		results[index] = result
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ParallelForEach calls fn for every element of items using at most workers goroutines.
+// If workers is less than or equal to 0, runtime.GOMAXPROCS(0) workers are used.
+// The first error returned by fn cancels the context passed to the remaining calls and is returned.
+func ParallelForEach[T any](ctx context.Context, items []T, workers int, fn func(context.Context, T) error) error {
+	return ParallelForEachIndex(ctx, items, workers, func(ctx context.Context, _ int, item T) error {
+		return fn(ctx, item)
+	})
+}
+
+// ParallelForEachIndex is the same as ParallelForEach, except that fn also receives the index of the element.
+func ParallelForEachIndex[T any](ctx context.Context, items []T, workers int, fn func(context.Context, int, T) error) error {
+	if len(items) == 0 {
+		return ctx.Err()
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	g, ctx := NewGroup(ctx)
+	indexes := make(chan int)
+	g.Go(func() error {
+		defer close(indexes)
+		for i := range items {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	for w := 0; w < workers; w++ {
+		g.Go(func() error {
+			for i := range indexes {
+				if err := fn(ctx, i, items[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
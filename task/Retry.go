@@ -0,0 +1,136 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrMaxAttempts is the cause of a RetryError when RetryPolicy.MaxAttempts is reached.
+	ErrMaxAttempts = errors.New("max attempts reached")
+	// ErrMaxElapsedTime is the cause of a RetryError when RetryPolicy.MaxElapsedTime is exceeded.
+	ErrMaxElapsedTime = errors.New("max elapsed time exceeded")
+)
+
+// RetryPolicy describes how Retry retries an operation.
+// The zero RetryPolicy retries immediately until the operation succeeds or the context is done.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls of the operation, including the first one. 0 means no limit.
+	MaxAttempts int
+	// MaxElapsedTime stops retrying once the time since the first call, plus the next delay, exceeds it.
+	// 0 means no limit.
+	MaxElapsedTime time.Duration
+	// Backoff computes the delay between two attempts. nil means no delay.
+	Backoff Backoff
+	// RetryIf reports whether a failed attempt should be retried. nil means every error is retried.
+	RetryIf func(error) bool
+	// OnRetry, if not nil, is called after a failed attempt, before waiting delay for the next one.
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// RetryError is returned by Retry when it gives up retrying an operation.
+type RetryError struct {
+	// Attempts is the number of calls of the operation.
+	Attempts int
+	// Err is the error returned by the last call of the operation.
+	Err error
+	// Cause is why Retry gave up: ErrMaxAttempts, ErrMaxElapsedTime or the context's error.
+	Cause error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("retry: %s after %d attempts: %s", e.Cause.Error(), e.Attempts, e.Err.Error())
+}
+
+// Unwrap makes both the last error of the operation and the cause visible to errors.Is and errors.As.
+func (e *RetryError) Unwrap() []error {
+	return []error{e.Err, e.Cause}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Retry stops retrying and returns err immediately,
+// whatever RetryPolicy.RetryIf says.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// IsPermanent returns true if err, or any error it wraps, was created by Permanent.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Retry calls op until it succeeds, returns a permanent or non retryable error, or the policy gives up.
+// An error rejected by RetryPolicy.RetryIf or wrapped by Permanent is returned as is,
+// otherwise giving up returns a *RetryError.
+//
+// Example:
+//
+//   err := task.Retry(ctx, task.RetryPolicy{
+//       MaxAttempts: 5,
+//       Backoff:     task.ExponentialBackoff(100*time.Millisecond, 2, 5*time.Second),
+//       OnRetry:     func(attempt int, err error, delay time.Duration) { log.Println(attempt, err, delay) },
+//   }, func(ctx context.Context) error {
+//       return db.PingContext(ctx)
+//   })
+func Retry(ctx context.Context, policy RetryPolicy, op func(context.Context) error) error {
+	start := time.Now()
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		err := op(ctx)
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if policy.RetryIf != nil && !policy.RetryIf(err) {
+			return err
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return &RetryError{Attempts: attempt, Err: err, Cause: ErrMaxAttempts}
+		}
+
+		if policy.Backoff != nil {
+			delay = policy.Backoff.Delay(attempt, delay)
+		}
+		if policy.MaxElapsedTime > 0 && time.Since(start)+delay > policy.MaxElapsedTime {
+			return &RetryError{Attempts: attempt, Err: err, Cause: ErrMaxElapsedTime}
+		}
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err, delay)
+		}
+
+		if ctxErr := sleep(ctx, delay); ctxErr != nil {
+			return &RetryError{Attempts: attempt, Err: err, Cause: ctxErr}
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,113 @@
+package task
+
+import (
+	"errors"
+	"sync"
+)
+
+var errGoexit = errors.New("task: singleflight function panicked or called runtime.Goexit")
+
+// SingleFlight collapses concurrent calls with the same key into a single execution whose result is
+// shared by all the callers. A zero SingleFlight is ready to use.
+//
+// Example:
+//
+//   var loads task.SingleFlight[string, *User]
+//   user, err, _ := loads.Do(id, func() (*User, error) { return db.LoadUser(id) })
+type SingleFlight[K comparable, V any] struct {
+	lock  sync.Mutex
+	calls map[K]*flightCall[V]
+}
+
+// FlightResult holds the results of SingleFlight.Do, so they can be passed on a channel.
+type FlightResult[V any] struct {
+	Val    V
+	Err    error
+	Shared bool
+}
+
+type flightCall[V any] struct {
+	wg    sync.WaitGroup
+	val   V
+	err   error
+	dups  int
+	chans []chan<- FlightResult[V]
+}
+
+// Do executes fn and returns its results, making sure that only one execution is in-flight for a given
+// key at a time. If a duplicate comes in, the duplicate caller waits for the original one to complete
+// and receives the same results.
+// Return value shared: true if the results were given to multiple callers.
+func (g *SingleFlight[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.lock.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*flightCall[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.lock.Unlock()
+		c.wg.Wait()
+		return c.val, c.err, true
+	}
+	c := new(flightCall[V])
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.lock.Unlock()
+
+	g.doCall(c, key, fn)
+	return c.val, c.err, c.dups > 0
+}
+
+// DoChan is like Do but returns a channel that will receive the results when they are ready.
+// The returned channel will not be closed.
+func (g *SingleFlight[K, V]) DoChan(key K, fn func() (V, error)) <-chan FlightResult[V] {
+	ch := make(chan FlightResult[V], 1)
+	g.lock.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*flightCall[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		c.chans = append(c.chans, ch)
+		g.lock.Unlock()
+		return ch
+	}
+	c := &flightCall[V]{chans: []chan<- FlightResult[V]{ch}}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.lock.Unlock()
+
+	go g.doCall(c, key, fn)
+	return ch
+}
+
+// Forget tells the SingleFlight to forget about a key. Future calls to Do for this key will call the
+// function rather than waiting for an earlier call to complete.
+func (g *SingleFlight[K, V]) Forget(key K) {
+	g.lock.Lock()
+	delete(g.calls, key)
+	g.lock.Unlock()
+}
+
+func (g *SingleFlight[K, V]) doCall(c *flightCall[V], key K, fn func() (V, error)) {
+	normalReturn := false
+	defer func() {
+		if !normalReturn {
+			// the waiters must not hang forever, the panic goes on in the calling goroutine
+			c.err = errGoexit
+		}
+		c.wg.Done()
+
+		g.lock.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		for _, ch := range c.chans {
+			ch <- FlightResult[V]{c.val, c.err, c.dups > 0}
+		}
+		g.lock.Unlock()
+	}()
+
+	c.val, c.err = fn()
+	normalReturn = true
+}